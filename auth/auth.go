@@ -6,9 +6,12 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -20,36 +23,88 @@ type TokenInfo struct {
 	Token       *oauth2.Token `json:"token"`
 	ClientID    string        `json:"client_id"`
 	LastRefresh time.Time     `json:"last_refresh"`
+	// Scopes are the scopes granted with the token
+	Scopes []string `json:"scopes,omitempty"`
 }
 
+// ErrNoToken is returned by GetToken when no token has been saved yet
+var ErrNoToken = errors.New("no saved token")
+
+// ErrReauthRequired is returned by GetToken when the saved token has
+// expired and cannot be refreshed, e.g. because access to the app was
+// revoked. Authenticate obtains a new one.
+var ErrReauthRequired = errors.New("saved token can no longer be refreshed")
+
 // AuthService handles Spotify authentication
 type AuthService struct {
 	config    *oauth2.Config
 	tokenFile string
 	token     *oauth2.Token
+	scopes    []string
+	state     string
+	
+	// tokenMu guards token and scopes, and state, verifier and the
+	// config's redirect URL and scopes of a pending login. The update loop,
+	// the token refresher and a login flow started from the UI use them
+	// concurrently. It is held during refreshes so only one runs at a time.
+	tokenMu sync.Mutex
+
+	// httpClient is the base client for oauth2, nil for the default
+	httpClient *http.Client
+	
+	// verifier is the PKCE code verifier of the pending authorization,
+	// pkce enables PKCE, which needs no client secret
+	pkce     bool
+	verifier string
+	
+	// store, when set, replaces the token file
+	store TokenStore
+	
+	// fallbackRedirects are tried by Authenticate when the redirect URI's
+	// port is busy
+	fallbackRedirects []string
+	
+	// showAuthURL presents the authorization URL to the user, nil to print it
+	showAuthURL func(authURL string)
 }
 
 // NewAuthService creates a new authentication service
-func NewAuthService(clientID, clientSecret, redirectURI string) *AuthService {
+func NewAuthService(clientID, clientSecret, redirectURI string, opts ...Option) *AuthService {
+	// The path of installations without XDG directories; callers pass
+	// config.TokenFile with WithTokenFile
 	homeDir, _ := os.UserHomeDir()
-	tokenFile := fmt.Sprintf("%s/.spotify-tmux/token.json", homeDir)
+	tokenFile := filepath.Join(homeDir, ".spotify-tmux", "token.json")
 	
 	config := &oauth2.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 		RedirectURL:  redirectURI,
-		Scopes: []string{
-			"user-read-playback-state",
-			"user-modify-playback-state",
-			"user-read-currently-playing",
-		},
-		Endpoint: spotify.Endpoint,
+		Scopes:       AllScopes(),
+		Endpoint:     spotify.Endpoint,
 	}
 	
-	return &AuthService{
+	a := &AuthService{
 		config:    config,
 		tokenFile: tokenFile,
 	}
+	
+	for _, opt := range opts {
+		opt(a)
+	}
+	
+	return a
+}
+
+// NewAuthServicePKCE creates an authentication service using the
+// Authorization Code flow with PKCE, which Spotify recommends for desktop
+// apps as it needs no client secret
+func NewAuthServicePKCE(clientID, redirectURI string, opts ...Option) *AuthService {
+	a := NewAuthService(clientID, "", redirectURI, opts...)
+	a.pkce = true
+	
+	// Without a secret the client ID has to be sent in the request body
+	a.config.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+	return a
 }
 
 // generateRandomState generates a random state for OAuth security
@@ -61,57 +116,131 @@ func generateRandomState() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
-// Authenticate starts the OAuth flow
-func (a *AuthService) Authenticate() error {
+// AuthCodeURL returns the URL the user must open to authorize the app.
+// The state embedded in the URL is remembered and checked by ExchangeCode.
+func (a *AuthService) AuthCodeURL() (string, error) {
+	authURL, _, err := a.authCodeURL(a.config)
+	return authURL, err
+}
+
+// authCodeURL is AuthCodeURL for an OAuth config, also returning the state
+func (a *AuthService) authCodeURL(config *oauth2.Config) (string, string, error) {
 	// Generate a random state for CSRF protection
 	state, err := generateRandomState()
 	if err != nil {
-		return err
+		return "", "", err
 	}
 	
-	// Create a channel to receive the authorization code
-	codeChan := make(chan string)
-	errChan := make(chan error)
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
 	
-	// Create an HTTP server for the callback
-	server := &http.Server{Addr: ":8080"}
+	a.state = state
 	
-	// Define the callback handler
-	http.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		// Verify state
-		if r.URL.Query().Get("state") != state {
-			errChan <- fmt.Errorf("state mismatch")
-			http.Error(w, "State mismatch", http.StatusBadRequest)
-			return
+	if !a.pkce {
+		return config.AuthCodeURL(state, oauth2.AccessTypeOffline), state, nil
+	}
+	
+	// Only the challenge is sent now, the verifier proves it in ExchangeCode
+	a.verifier = oauth2.GenerateVerifier()
+	return config.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(a.verifier)), state, nil
+}
+
+// ExchangeCode completes the flow with an authorization code obtained
+// by the caller, e.g. through its own redirect handling. If AuthCodeURL was
+// used to build the authorization URL, state must match the one it embedded.
+func (a *AuthService) ExchangeCode(code, state string) error {
+	return a.exchangeCode(a.config, code, state)
+}
+
+// exchangeCode is ExchangeCode for an OAuth config
+func (a *AuthService) exchangeCode(config *oauth2.Config, code, state string) error {
+	if code == "" {
+		return fmt.Errorf("no authorization code given")
+	}
+	
+	a.tokenMu.Lock()
+	expected, verifier := a.state, a.verifier
+	a.tokenMu.Unlock()
+	
+	if expected != "" && state != expected {
+		return fmt.Errorf("state mismatch")
+	}
+	
+	// Exchange the code for a token
+	var exchangeOpts []oauth2.AuthCodeOption
+	if a.pkce {
+		if verifier == "" {
+			return fmt.Errorf("no PKCE verifier, call AuthCodeURL first")
 		}
-		
-		// Get the code
-		code := r.URL.Query().Get("code")
-		if code == "" {
-			errChan <- fmt.Errorf("no code in response")
-			http.Error(w, "No code in response", http.StatusBadRequest)
-			return
+		exchangeOpts = append(exchangeOpts, oauth2.VerifierOption(verifier))
+	}
+	token, err := config.Exchange(a.context(), code, exchangeOpts...)
+	if err != nil {
+		return err
+	}
+	
+	// Save the token
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
+	a.state = ""
+	a.verifier = ""
+	a.token = token
+	a.scopes = tokenScopes(token, a.config.Scopes)
+	return a.saveToken()
+}
+
+// Authenticate runs the OAuth flow using a local callback server. The
+// server listens on the first redirect URI whose port is free.
+func (a *AuthService) Authenticate() error {
+	listener, redirectURI, callbackPath, err := a.listenRedirect()
+	if err != nil {
+		return err
+	}
+	
+	// The code exchange must use the redirect URI the user was sent to. It
+	// may be a fallback, so change a copy and start the next login from the
+	// main one again.
+	config := *a.config
+	config.RedirectURL = redirectURI
+	
+	// Generate the auth URL
+	authURL, state, err := a.authCodeURL(&config)
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	
+	// Create a channel to receive the authorization code. Only the first
+	// result is used, later ones are dropped so that handlers never block
+	// and the server can always shut down.
+	codeChan := make(chan string, 1)
+	errChan := make(chan error, 1)
+	report := func(err error) {
+		select {
+		case errChan <- err:
+		default:
 		}
-		
-		// Send success page
-		fmt.Fprint(w, "Authentication successful! You can now close this window.")
-		
-		// Send the code to the channel
-		codeChan <- code
-	})
+	}
+	
+	// Create an HTTP server for the callback. Each attempt gets its own mux,
+	// so Authenticate can run more than once in a process.
+	mux := http.NewServeMux()
+	mux.Handle(callbackPath, callbackHandler(state, codeChan, report))
+	server := &http.Server{Handler: mux}
 	
 	// Start the server in a goroutine
 	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errChan <- err
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			report(err)
 		}
 	}()
 	
-	// Generate the auth URL
-	authURL := a.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
-	
 	// Print the auth URL
-	fmt.Printf("Please open the following URL in your browser:\n%s\n", authURL)
+	if a.showAuthURL != nil {
+		a.showAuthURL(authURL)
+	} else {
+		fmt.Printf("Please open the following URL in your browser:\n%s\n", authURL)
+	}
 	
 	// Wait for the code or error
 	var code string
@@ -131,19 +260,64 @@ func (a *AuthService) Authenticate() error {
 	// Shutdown the server
 	server.Shutdown(context.Background())
 	
-	// Exchange the code for a token
-	token, err := a.config.Exchange(context.Background(), code)
-	if err != nil {
-		return err
+	return a.exchangeCode(&config, code, state)
+}
+
+// callbackHandler handles the redirect back from Spotify. It sends the
+// authorization code to codeChan if state matches, without blocking, and
+// reports failures.
+func callbackHandler(state string, codeChan chan<- string, report func(error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Verify state
+		if r.URL.Query().Get("state") != state {
+			report(fmt.Errorf("state mismatch"))
+			http.Error(w, "State mismatch", http.StatusBadRequest)
+			return
+		}
+		
+		// Get the code
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			report(fmt.Errorf("no code in response"))
+			http.Error(w, "No code in response", http.StatusBadRequest)
+			return
+		}
+		
+		// Send success page
+		fmt.Fprint(w, "Authentication successful! You can now close this window.")
+		
+		// Send the code to the channel
+		select {
+		case codeChan <- code:
+		default:
+		}
 	}
+}
+
+// Logout forgets the token by deleting the token file (or clearing the
+// token store). It is not an error if there was no token.
+func (a *AuthService) Logout() error {
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
 	
-	// Save the token
-	a.token = token
-	return a.saveToken()
+	a.token = nil
+	a.scopes = nil
+	
+	if a.store != nil {
+		return a.store.Save(&TokenInfo{})
+	}
+	
+	if err := os.Remove(a.tokenFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
 
 // HasValidToken checks if a valid token exists
 func (a *AuthService) HasValidToken() bool {
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
+	
 	if a.token != nil && a.token.Valid() {
 		return true
 	}
@@ -158,6 +332,9 @@ func (a *AuthService) HasValidToken() bool {
 
 // GetToken returns the OAuth token
 func (a *AuthService) GetToken() (*oauth2.Token, error) {
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
+	
 	if a.token == nil {
 		if err := a.loadToken(); err != nil {
 			return nil, err
@@ -167,8 +344,11 @@ func (a *AuthService) GetToken() (*oauth2.Token, error) {
 	// Check if token needs refresh
 	if a.token != nil && !a.token.Valid() {
 		// Refresh the token
-		newToken, err := a.config.TokenSource(context.Background(), a.token).Token()
+		newToken, err := a.config.TokenSource(a.context(), a.token).Token()
 		if err != nil {
+			if refreshRejected(err) || a.token.RefreshToken == "" {
+				return nil, fmt.Errorf("%w: %w", ErrReauthRequired, err)
+			}
 			return nil, err
 		}
 		
@@ -181,60 +361,118 @@ func (a *AuthService) GetToken() (*oauth2.Token, error) {
 	return a.token, nil
 }
 
-// GetClient returns an HTTP client with authentication
+// refreshRejected reports whether a refresh failed because Spotify no
+// longer accepts the refresh token, as opposed to a temporary failure such
+// as a network error, a 5xx or a 429 from the accounts service
+func refreshRejected(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	if !errors.As(err, &retrieveErr) {
+		return false
+	}
+	if retrieveErr.ErrorCode == "invalid_grant" {
+		return true
+	}
+	if retrieveErr.Response == nil {
+		return false
+	}
+	status := retrieveErr.Response.StatusCode
+	return status == http.StatusBadRequest || status == http.StatusUnauthorized
+}
+
+// GetClient returns an HTTP client with authentication. It takes the token
+// from GetToken for every request, so it never holds on to a stale token
+// after a refresh, Logout or logging in again.
 func (a *AuthService) GetClient() (*http.Client, error) {
-	token, err := a.GetToken()
-	if err != nil {
+	if _, err := a.GetToken(); err != nil {
 		return nil, err
 	}
 	
-	return a.config.Client(context.Background(), token), nil
+	base := http.DefaultTransport
+	if a.httpClient != nil && a.httpClient.Transport != nil {
+		base = a.httpClient.Transport
+	}
+	return &http.Client{Transport: &oauth2.Transport{Source: tokenSource{a}, Base: base}}, nil
 }
 
-// loadToken loads the token from file
+// tokenSource makes GetToken an oauth2.TokenSource
+type tokenSource struct {
+	a *AuthService
+}
+
+// Token implements oauth2.TokenSource
+func (s tokenSource) Token() (*oauth2.Token, error) {
+	return s.a.GetToken()
+}
+
+// loadToken loads the token from the token store or file.
+// a.tokenMu must be held.
 func (a *AuthService) loadToken() error {
-	// Check if token file exists
-	if _, err := os.Stat(a.tokenFile); os.IsNotExist(err) {
-		return fmt.Errorf("token file does not exist")
-	}
-	
-	// Read the token file
-	data, err := os.ReadFile(a.tokenFile)
-	if err != nil {
-		return err
+	var tokenInfo *TokenInfo
+	if a.store != nil {
+		info, err := a.store.Load()
+		if err != nil {
+			return err
+		}
+		tokenInfo = info
+	} else {
+		// Check if token file exists
+		if _, err := os.Stat(a.tokenFile); os.IsNotExist(err) {
+			return ErrNoToken
+		}
+		
+		// Read the token file
+		data, err := os.ReadFile(a.tokenFile)
+		if err != nil {
+			return err
+		}
+		
+		// Parse the token
+		tokenInfo = &TokenInfo{}
+		if err := json.Unmarshal(data, tokenInfo); err != nil {
+			return fmt.Errorf("%w: %s is not a valid token file: %v", ErrNoToken, a.tokenFile, err)
+		}
 	}
 	
-	// Parse the token
-	var tokenInfo TokenInfo
-	if err := json.Unmarshal(data, &tokenInfo); err != nil {
-		return err
+	if tokenInfo == nil || tokenInfo.Token == nil {
+		return ErrNoToken
 	}
 	
 	a.token = tokenInfo.Token
+	a.scopes = tokenInfo.Scopes
+	if a.scopes == nil {
+		a.scopes = legacyScopes
+	}
 	return nil
 }
 
-// saveToken saves the token to file
-func (a *AuthService) saveToken() error {
-	// Ensure directory exists
-	dir := fmt.Sprintf("%s/.spotify-tmux", os.Getenv("HOME"))
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-	
-	// Create token info
-	tokenInfo := TokenInfo{
+// tokenInfo returns the current token as it is saved. a.tokenMu must be held.
+func (a *AuthService) tokenInfo() *TokenInfo {
+	return &TokenInfo{
 		Token:       a.token,
 		ClientID:    a.config.ClientID,
 		LastRefresh: time.Now(),
+		Scopes:      a.scopes,
+	}
+}
+
+// saveToken saves the token to the token store or file.
+// a.tokenMu must be held.
+func (a *AuthService) saveToken() error {
+	if a.store != nil {
+		return a.store.Save(a.tokenInfo())
+	}
+	
+	// Ensure directory exists
+	if err := os.MkdirAll(filepath.Dir(a.tokenFile), 0755); err != nil {
+		return err
 	}
 	
 	// Serialize the token
-	data, err := json.MarshalIndent(tokenInfo, "", "  ")
+	data, err := json.MarshalIndent(a.tokenInfo(), "", "  ")
 	if err != nil {
 		return err
 	}
 	
 	// Write to file
 	return os.WriteFile(a.tokenFile, data, 0600)
-}
\ No newline at end of file
+}
@@ -0,0 +1,155 @@
+// auth/auth_test.go
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// newTestAuthService returns an AuthService that keeps its token in memory
+// and refreshes it against tokenURL
+func newTestAuthService(t *testing.T, tokenURL string, token *oauth2.Token) *AuthService {
+	t.Helper()
+	store := NewMemoryTokenStore(&TokenInfo{Token: token})
+	a := NewAuthService("id", "secret", "http://127.0.0.1:8080/callback", WithTokenStore(store))
+	a.config.Endpoint = oauth2.Endpoint{TokenURL: tokenURL, AuthStyle: oauth2.AuthStyleInParams}
+	return a
+}
+
+// expiredToken is a token that must be refreshed before use
+func expiredToken() *oauth2.Token {
+	return &oauth2.Token{
+		AccessToken:  "old",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(-time.Hour),
+	}
+}
+
+func TestGetTokenRefreshFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		reauth bool
+	}{
+		{"invalid grant", http.StatusBadRequest, `{"error": "invalid_grant", "error_description": "Refresh token revoked"}`, true},
+		{"bad request", http.StatusBadRequest, `{"error": "invalid_request"}`, true},
+		{"unauthorized client", http.StatusUnauthorized, `{"error": "invalid_client"}`, true},
+		{"server error", http.StatusServiceUnavailable, `upstream unavailable`, false},
+		{"rate limited", http.StatusTooManyRequests, `{"error": "too_many_requests"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			a := newTestAuthService(t, server.URL, expiredToken())
+			_, err := a.GetToken()
+			if err == nil {
+				t.Fatal("GetToken succeeded, want an error")
+			}
+			if got := errors.Is(err, ErrReauthRequired); got != tt.reauth {
+				t.Errorf("errors.Is(%v, ErrReauthRequired) = %v, want %v", err, got, tt.reauth)
+			}
+		})
+	}
+}
+
+func TestGetTokenWithoutRefreshToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no refresh should be attempted")
+	}))
+	defer server.Close()
+
+	token := expiredToken()
+	token.RefreshToken = ""
+	a := newTestAuthService(t, server.URL, token)
+	if _, err := a.GetToken(); !errors.Is(err, ErrReauthRequired) {
+		t.Errorf("GetToken() error = %v, want ErrReauthRequired", err)
+	}
+}
+
+func TestGetTokenRefreshes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if got := r.PostForm.Get("refresh_token"); got != "refresh" {
+			t.Errorf("refresh_token = %q, want %q", got, "refresh")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "new", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+
+	a := newTestAuthService(t, server.URL, expiredToken())
+	token, err := a.GetToken()
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if token.AccessToken != "new" {
+		t.Errorf("AccessToken = %q, want %q", token.AccessToken, "new")
+	}
+
+	// The refreshed token is saved
+	info, err := a.store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Token.AccessToken != "new" {
+		t.Errorf("saved AccessToken = %q, want %q", info.Token.AccessToken, "new")
+	}
+}
+
+func TestClientUsesTokenAfterRelogin(t *testing.T) {
+	var seen []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+	}))
+	defer api.Close()
+
+	a := newTestAuthService(t, "http://127.0.0.1:1/token", &oauth2.Token{
+		AccessToken: "first",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+	client, err := a.GetClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	get(t, client, api.URL)
+
+	// Log out and in again, as the login flow stores the new token
+	if err := a.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	a.tokenMu.Lock()
+	a.token = &oauth2.Token{AccessToken: "second", Expiry: time.Now().Add(time.Hour)}
+	a.tokenMu.Unlock()
+
+	get(t, client, api.URL)
+
+	want := []string{"Bearer first", "Bearer second"}
+	if len(seen) != len(want) || seen[0] != want[0] || seen[1] != want[1] {
+		t.Errorf("requests sent %q, want %q", seen, want)
+	}
+}
+
+// get requests url with client and discards the response
+func get(t *testing.T, client *http.Client, url string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,65 @@
+// auth/callback.go
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// WithFallbackRedirectURIs adds redirect URIs that Authenticate tries, in
+// order, when the port of the main redirect URI is already in use. Each must
+// also be registered for the app on the Spotify dashboard.
+func WithFallbackRedirectURIs(uris []string) Option {
+	return func(a *AuthService) {
+		a.fallbackRedirects = append(a.fallbackRedirects, uris...)
+	}
+}
+
+// redirectCandidates returns the redirect URIs to try, main one first
+func (a *AuthService) redirectCandidates() []string {
+	candidates := []string{a.config.RedirectURL}
+	for _, uri := range a.fallbackRedirects {
+		if uri != "" && uri != a.config.RedirectURL {
+			candidates = append(candidates, uri)
+		}
+	}
+	return candidates
+}
+
+// listenRedirect binds the first candidate redirect URI whose port is free
+// and returns the listener, the chosen URI and its callback path
+func (a *AuthService) listenRedirect() (net.Listener, string, string, error) {
+	var errs []error
+	for _, candidate := range a.redirectCandidates() {
+		u, err := url.Parse(candidate)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", candidate, err))
+			continue
+		}
+		if u.Scheme != "http" {
+			errs = append(errs, fmt.Errorf("%s: only http redirect URIs can be served locally", candidate))
+			continue
+		}
+
+		addr := u.Host
+		if u.Port() == "" {
+			addr = net.JoinHostPort(u.Hostname(), "80")
+		}
+
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		path := u.Path
+		if path == "" {
+			path = "/"
+		}
+		return listener, candidate, path, nil
+	}
+
+	return nil, "", "", fmt.Errorf("could not listen on any redirect URI: %w", errors.Join(errs...))
+}
@@ -0,0 +1,206 @@
+// auth/callback_test.go
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		status   int
+		code     string
+		reported bool
+	}{
+		{"success", "state=s&code=c", http.StatusOK, "c", false},
+		{"state mismatch", "state=other&code=c", http.StatusBadRequest, "", true},
+		{"no code", "state=s&error=access_denied", http.StatusBadRequest, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeChan := make(chan string, 1)
+			var reported error
+			handler := callbackHandler("s", codeChan, func(err error) { reported = err })
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", "/callback?"+tt.query, nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if (reported != nil) != tt.reported {
+				t.Errorf("reported error = %v, want one: %v", reported, tt.reported)
+			}
+			select {
+			case code := <-codeChan:
+				if code != tt.code {
+					t.Errorf("code = %q, want %q", code, tt.code)
+				}
+			default:
+				if tt.code != "" {
+					t.Errorf("no code sent, want %q", tt.code)
+				}
+			}
+		})
+	}
+}
+
+func TestCallbackHandlerDoesNotBlock(t *testing.T) {
+	codeChan := make(chan string, 1)
+	handler := callbackHandler("s", codeChan, func(error) {})
+
+	// A second callback, e.g. a reload of the page, must not block
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 2; i++ {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/callback?state=s&code=c", nil))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("callback handler blocked")
+	}
+}
+
+// freePort returns a local port that was free a moment ago
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// followAuthURL plays the browser: it follows the redirect back with a code,
+// reporting failures on errs
+func followAuthURL(errs chan<- error) func(authURL string) {
+	return func(authURL string) {
+		go func() {
+			u, err := url.Parse(authURL)
+			if err != nil {
+				errs <- err
+				return
+			}
+			query := u.Query()
+			callback := query.Get("redirect_uri") + "?code=code&state=" + url.QueryEscape(query.Get("state"))
+			resp, err := http.Get(callback)
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				errs <- errors.New(resp.Status)
+			}
+		}()
+	}
+}
+
+func TestAuthenticateTwice(t *testing.T) {
+	exchanges := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token-%d", "refresh_token": "refresh", "token_type": "Bearer", "expires_in": 3600}`, exchanges)
+	}))
+	defer tokenServer.Close()
+
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", freePort(t))
+	a := NewAuthService("id", "secret", redirectURI, WithTokenFile(filepath.Join(t.TempDir(), "token.json")))
+	a.config.Endpoint = oauth2.Endpoint{
+		AuthURL:   "https://accounts.example/authorize",
+		TokenURL:  tokenServer.URL,
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+
+	callbackErrs := make(chan error, 1)
+	a.showAuthURL = followAuthURL(callbackErrs)
+
+	for i := 1; i <= 2; i++ {
+		if err := a.Authenticate(); err != nil {
+			t.Fatalf("Authenticate() #%d error = %v", i, err)
+		}
+		select {
+		case err := <-callbackErrs:
+			t.Fatalf("callback #%d failed: %v", i, err)
+		default:
+		}
+
+		token, err := a.GetToken()
+		if err != nil {
+			t.Fatalf("GetToken() after login #%d error = %v", i, err)
+		}
+		if want := fmt.Sprintf("token-%d", i); token.AccessToken != want {
+			t.Errorf("AccessToken after login #%d = %q, want %q", i, token.AccessToken, want)
+		}
+	}
+}
+
+func TestAuthenticateFallbackIsNotKept(t *testing.T) {
+	var redirects []string
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		redirects = append(redirects, r.Form.Get("redirect_uri"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "token", "refresh_token": "refresh", "token_type": "Bearer", "expires_in": 3600}`)
+	}))
+	defer tokenServer.Close()
+
+	// Keep the main redirect URI's port busy
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	redirectURI := fmt.Sprintf("http://%s/callback", busy.Addr())
+	fallback := fmt.Sprintf("http://127.0.0.1:%d/callback", freePort(t))
+	a := NewAuthService("id", "secret", redirectURI,
+		WithTokenFile(filepath.Join(t.TempDir(), "token.json")),
+		WithFallbackRedirectURIs([]string{fallback}))
+	a.config.Endpoint = oauth2.Endpoint{
+		AuthURL:   "https://accounts.example/authorize",
+		TokenURL:  tokenServer.URL,
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+
+	callbackErrs := make(chan error, 1)
+	a.showAuthURL = followAuthURL(callbackErrs)
+	if err := a.Authenticate(); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	select {
+	case err := <-callbackErrs:
+		t.Fatalf("callback failed: %v", err)
+	default:
+	}
+
+	if len(redirects) != 1 || redirects[0] != fallback {
+		t.Errorf("exchanged the code with redirect URIs %q, want the fallback %s", redirects, fallback)
+	}
+	if a.config.RedirectURL != redirectURI {
+		t.Errorf("RedirectURL = %s after the login, want the main %s", a.config.RedirectURL, redirectURI)
+	}
+
+	// The next login tries the main redirect URI first again
+	if got := a.redirectCandidates(); got[0] != redirectURI {
+		t.Errorf("redirect candidates = %q, want %s first", got, redirectURI)
+	}
+}
@@ -0,0 +1,85 @@
+// auth/refresh.go
+package auth
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// refreshMargin is how long before expiry the refresher renews the token
+	refreshMargin = 2 * time.Minute
+	// refreshRetry is how long the refresher waits after a failed refresh
+	refreshRetry = time.Minute
+)
+
+// StartTokenRefresher renews the token in the background shortly before it
+// expires and saves it, so API calls never run into an expired token. It
+// returns immediately and stops when ctx is cancelled.
+func (a *AuthService) StartTokenRefresher(ctx context.Context) {
+	go func() {
+		for {
+			wait, ok := a.untilRefresh()
+			if !ok {
+				return
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			if err := a.refresh(); err != nil {
+				// GetToken still refreshes on demand, so just try again later
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(refreshRetry):
+				}
+			}
+		}
+	}()
+}
+
+// untilRefresh returns how long to wait before the next refresh. It
+// reports false when there is nothing to refresh.
+func (a *AuthService) untilRefresh() (time.Duration, bool) {
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
+
+	if a.token == nil || a.token.RefreshToken == "" || a.token.Expiry.IsZero() {
+		return 0, false
+	}
+
+	wait := time.Until(a.token.Expiry) - refreshMargin
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+// refresh renews the token regardless of its expiry and saves it
+func (a *AuthService) refresh() error {
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
+
+	if a.token == nil {
+		return nil
+	}
+
+	// A token without an access token counts as expired, which forces the
+	// token source to use the refresh token
+	expired := &oauth2.Token{RefreshToken: a.token.RefreshToken}
+	newToken, err := a.config.TokenSource(a.context(), expired).Token()
+	if err != nil {
+		return err
+	}
+
+	a.token = newToken
+	return a.saveToken()
+}
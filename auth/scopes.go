@@ -0,0 +1,135 @@
+// auth/scopes.go
+package auth
+
+import (
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// Scopes needed by the player's features
+var (
+	// ReadScopes let the player show what is playing
+	ReadScopes = []string{"user-read-playback-state", "user-read-currently-playing"}
+	// ControlScopes let the player control playback
+	ControlScopes = []string{"user-modify-playback-state"}
+	// ProfileScopes let the player read the account tier
+	ProfileScopes = []string{"user-read-private"}
+	// LibraryScopes let the player like and unlike tracks
+	LibraryScopes = []string{"user-library-read", "user-library-modify"}
+	// HistoryScopes let the player read the listening history
+	HistoryScopes = []string{"user-read-recently-played"}
+	// PlaylistScopes let the player list the user's private and
+	// collaborative playlists
+	PlaylistScopes = []string{"playlist-read-private", "playlist-read-collaborative"}
+)
+
+// scopeFeatures names the feature each scope set is needed for
+var scopeFeatures = []struct {
+	feature string
+	scopes  []string
+}{
+	{"now playing", ReadScopes},
+	{"playback control", ControlScopes},
+	{"account tier", ProfileScopes},
+	{"likes", LibraryScopes},
+	{"history", HistoryScopes},
+	{"playlists", PlaylistScopes},
+}
+
+// ScopeHelp lists which features need which scopes, for error messages,
+// e.g. "now playing: user-read-playback-state ...; playback control: ..."
+func ScopeHelp() string {
+	parts := make([]string, len(scopeFeatures))
+	for i, f := range scopeFeatures {
+		parts[i] = f.feature + ": " + strings.Join(f.scopes, " ")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// AllScopes returns the scopes of every feature
+func AllScopes() []string {
+	return mergeScopes(ReadScopes, ControlScopes, ProfileScopes, LibraryScopes, HistoryScopes, PlaylistScopes)
+}
+
+// legacyScopes are assumed for token files written before scopes were
+// recorded, which were always requested with this set
+var legacyScopes = []string{
+	"user-read-playback-state",
+	"user-modify-playback-state",
+	"user-read-currently-playing",
+}
+
+// WithScopes sets the scopes requested when logging in. Further scopes can
+// be requested later with RequestScopes.
+func WithScopes(scopes []string) Option {
+	return func(a *AuthService) {
+		if len(scopes) > 0 {
+			a.config.Scopes = mergeScopes(scopes)
+		}
+	}
+}
+
+// GrantedScopes returns the scopes of the current token
+func (a *AuthService) GrantedScopes() []string {
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
+
+	if a.token == nil {
+		a.loadToken()
+	}
+	return append([]string(nil), a.scopes...)
+}
+
+// HasScopes reports whether the current token grants all of scopes
+func (a *AuthService) HasScopes(scopes ...string) bool {
+	granted := make(map[string]bool)
+	for _, scope := range a.GrantedScopes() {
+		granted[scope] = true
+	}
+	for _, scope := range scopes {
+		if !granted[scope] {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestScopes makes sure the token grants scopes, running the login flow
+// again for the scopes already granted plus the new ones if it does not.
+// It does nothing when all of them are already granted.
+func (a *AuthService) RequestScopes(scopes ...string) error {
+	if a.HasScopes(scopes...) {
+		return nil
+	}
+
+	granted := a.GrantedScopes()
+	a.tokenMu.Lock()
+	a.config.Scopes = mergeScopes(granted, a.config.Scopes, scopes)
+	a.tokenMu.Unlock()
+	return a.Authenticate()
+}
+
+// tokenScopes returns the scopes Spotify granted with a token, falling back
+// to the requested ones when the response does not list them
+func tokenScopes(token *oauth2.Token, requested []string) []string {
+	if scope, ok := token.Extra("scope").(string); ok && scope != "" {
+		return mergeScopes(strings.Fields(scope))
+	}
+	return mergeScopes(requested)
+}
+
+// mergeScopes returns the union of the scope sets, in order of first appearance
+func mergeScopes(sets ...[]string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, set := range sets {
+		for _, scope := range set {
+			if scope != "" && !seen[scope] {
+				seen[scope] = true
+				merged = append(merged, scope)
+			}
+		}
+	}
+	return merged
+}
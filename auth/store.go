@@ -0,0 +1,118 @@
+// auth/store.go
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenStore keeps the token somewhere other than the token file
+type TokenStore interface {
+	// Load returns the stored token, or an error if there is none
+	Load() (*TokenInfo, error)
+	Save(info *TokenInfo) error
+}
+
+// WithTokenStore keeps the token in store instead of the token file
+func WithTokenStore(store TokenStore) Option {
+	return func(a *AuthService) {
+		a.store = store
+	}
+}
+
+// WithTokenFile sets the file the token is saved to. An empty path keeps the
+// default, ~/.spotify-tmux/token.json.
+func WithTokenFile(path string) Option {
+	return func(a *AuthService) {
+		if path != "" {
+			a.tokenFile = path
+		}
+	}
+}
+
+// MemoryTokenStore keeps the token in memory only, for sessions that must
+// not read or write the token file
+type MemoryTokenStore struct {
+	mu   sync.Mutex
+	info *TokenInfo
+}
+
+// NewMemoryTokenStore creates a store holding info, which may be nil
+func NewMemoryTokenStore(info *TokenInfo) *MemoryTokenStore {
+	return &MemoryTokenStore{info: info}
+}
+
+// Load returns the token held in memory
+func (s *MemoryTokenStore) Load() (*TokenInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.info == nil {
+		return nil, errors.New("no token in memory")
+	}
+	info := *s.info
+	return &info, nil
+}
+
+// Save replaces the token held in memory
+func (s *MemoryTokenStore) Save(info *TokenInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *info
+	s.info = &saved
+	return nil
+}
+
+// ReadTokenInfo reads token JSON as written to the token file. A bare
+// OAuth token object ({"access_token": ...}) is accepted as well.
+func ReadTokenInfo(r io.Reader) (*TokenInfo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var info TokenInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("invalid token JSON: %w", err)
+	}
+
+	if info.Token == nil {
+		var token oauth2.Token
+		if err := json.Unmarshal(data, &token); err != nil {
+			return nil, fmt.Errorf("invalid token JSON: %w", err)
+		}
+		info.Token = &token
+	}
+
+	if info.Token.AccessToken == "" && info.Token.RefreshToken == "" {
+		return nil, errors.New("token JSON holds neither an access nor a refresh token")
+	}
+
+	return &info, nil
+}
+
+// TokenInfo returns the current token as it would be saved, refreshing it
+// first if it has expired
+func (a *AuthService) TokenInfo() (*TokenInfo, error) {
+	if _, err := a.GetToken(); err != nil {
+		return nil, err
+	}
+
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
+	return a.tokenInfo(), nil
+}
+
+// Redact shortens a secret so it can be shown in logs without revealing it
+func Redact(secret string) string {
+	if len(secret) <= 8 {
+		return "****"
+	}
+	return secret[:4] + "…" + secret[len(secret)-4:]
+}
@@ -0,0 +1,63 @@
+// auth/store_test.go
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestWithTokenFile(t *testing.T) {
+	// The directory does not exist yet and is created on save
+	path := filepath.Join(t.TempDir(), "state", "token.json")
+
+	a := NewAuthService("id", "secret", "http://127.0.0.1:8080/callback", WithTokenFile(path))
+	a.tokenMu.Lock()
+	a.token = &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}
+	a.scopes = []string{"user-read-playback-state"}
+	err := a.saveToken()
+	a.tokenMu.Unlock()
+	if err != nil {
+		t.Fatalf("saveToken() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("token file not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("token file mode = %o, want 600", perm)
+	}
+
+	loaded := NewAuthService("id", "secret", "http://127.0.0.1:8080/callback", WithTokenFile(path))
+	token, err := loaded.GetToken()
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" {
+		t.Errorf("loaded token = %+v, want the saved one", token)
+	}
+	if !loaded.HasScopes("user-read-playback-state") {
+		t.Errorf("GrantedScopes() = %v, want the saved scopes", loaded.GrantedScopes())
+	}
+}
+
+func TestWithTokenFileMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	a := NewAuthService("id", "secret", "http://127.0.0.1:8080/callback", WithTokenFile(path))
+	if _, err := a.GetToken(); err != ErrNoToken {
+		t.Errorf("GetToken() error = %v, want ErrNoToken", err)
+	}
+}
+
+func TestWithTokenFileEmptyKeepsDefault(t *testing.T) {
+	a := NewAuthService("id", "secret", "http://127.0.0.1:8080/callback")
+	def := a.tokenFile
+	WithTokenFile("")(a)
+	if a.tokenFile != def {
+		t.Errorf("tokenFile = %q, want the default %q", a.tokenFile, def)
+	}
+}
@@ -0,0 +1,68 @@
+// auth/transport.go
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"golang.org/x/oauth2"
+)
+
+// Option configures an AuthService
+type Option func(*AuthService)
+
+// WithTransport sets the base transport used for the token exchange, token
+// refreshes and API requests, e.g. one trusting a corporate CA. A
+// *http.Transport without a Proxy function is given http.ProxyFromEnvironment
+// so HTTP_PROXY, HTTPS_PROXY and NO_PROXY keep working.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(a *AuthService) {
+		if t, ok := transport.(*http.Transport); ok && t.Proxy == nil {
+			t = t.Clone()
+			t.Proxy = http.ProxyFromEnvironment
+			transport = t
+		}
+		a.httpClient = &http.Client{Transport: transport}
+	}
+}
+
+// NewTransport returns a proxy-aware transport that additionally trusts the
+// PEM certificates in caFile. An empty caFile only uses the system roots.
+func NewTransport(caFile string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if caFile == "" {
+		return transport, nil
+	}
+
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return transport, nil
+}
+
+// context returns the context passed to oauth2, carrying the custom
+// HTTP client when one is configured
+func (a *AuthService) context() context.Context {
+	ctx := context.Background()
+	if a.httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, a.httpClient)
+	}
+	return ctx
+}
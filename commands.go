@@ -0,0 +1,114 @@
+// commands.go
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mesyrob/spotify-tmux/config"
+)
+
+// runCommand runs a subcommand and returns the process exit code.
+// ok is false when args do not name a known subcommand.
+func runCommand(args []string) (code int, ok bool) {
+	switch args[0] {
+	case "config":
+		return runConfig(args[1:]), true
+	case "setup":
+		return runSetup(args[1:]), true
+	case "export":
+		return runExport(args[1:]), true
+	case "doctor":
+		return runDoctor(args[1:]), true
+	case "profiles":
+		return runProfiles(args[1:]), true
+	case "play", "pause", "next", "prev", "toggle":
+		return runControl(args[0], args[1:]), true
+	}
+	return 0, false
+}
+
+// credentialsHelp is printed when no client credentials are configured
+const credentialsHelp = `spotify-tmux needs the Client ID and Client Secret of a Spotify app.
+
+The quickest way to set them up is the setup wizard:
+
+    spotify-tmux setup
+
+Or provide them yourself, in any of these places:
+
+    environment   SPOTIFY_CLIENT_ID and SPOTIFY_CLIENT_SECRET
+    credentials   %s
+    config        %s
+    .env file     CLIENT_ID and CLIENT_SECRET in the current directory
+
+Create the app at https://developer.spotify.com/dashboard
+`
+
+// printSetupError explains a failure to set up the services on stderr
+func printSetupError(err error) {
+	if errors.Is(err, config.ErrMissingCredentials) {
+		credsPath, _ := config.CredentialsPath()
+		configPath, _ := config.Path()
+		fmt.Fprintf(os.Stderr, credentialsHelp, credsPath, configPath)
+		return
+	}
+	fmt.Fprintln(os.Stderr, err)
+}
+
+// runConfig implements `spotify-tmux config <action>`
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "init" {
+		fmt.Fprintln(os.Stderr, "usage: spotify-tmux config init [-force]")
+		return 2
+	}
+
+	flags := flag.NewFlagSet("config init", flag.ContinueOnError)
+	force := flags.Bool("force", false, "overwrite an existing config file")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	path, err := config.Path()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to locate config file: %v\n", err)
+		return 1
+	}
+
+	if err := config.WriteDefault(path, *force); err != nil {
+		if errors.Is(err, config.ErrConfigExists) {
+			fmt.Fprintf(os.Stderr, "%s already exists, use -force to overwrite it\n", path)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Failed to write config file: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Wrote default configuration to %s\n", path)
+	return 0
+}
+
+// runProfiles implements `spotify-tmux profiles`, listing the named profiles
+func runProfiles(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "usage: spotify-tmux profiles")
+		return 2
+	}
+
+	profiles, err := config.Profiles()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list profiles: %v\n", err)
+		return 1
+	}
+	if len(profiles) == 0 {
+		fmt.Fprintln(os.Stderr, "No profiles yet, create one with spotify-tmux -profile NAME setup")
+		return 0
+	}
+
+	for _, name := range profiles {
+		fmt.Println(name)
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+// config/bookmarks.go
+package config
+
+import (
+	"fmt"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// MaxBookmarks is the number of bookmarks reachable with the number keys
+const MaxBookmarks = 9
+
+// Bookmark is a playlist, album, artist or show played with a number key
+type Bookmark struct {
+	Label string `json:"label"`
+	URI   string `json:"uri"`
+}
+
+// bookmarkKinds are the URI kinds a bookmark can point to
+var bookmarkKinds = map[string]bool{
+	"playlist": true,
+	"album":    true,
+	"artist":   true,
+	"show":     true,
+}
+
+// validateBookmarks checks the bookmark count and URIs
+func validateBookmarks(bookmarks []Bookmark) error {
+	if len(bookmarks) > MaxBookmarks {
+		return fmt.Errorf("at most %d bookmarks are supported, got %d", MaxBookmarks, len(bookmarks))
+	}
+
+	for i, b := range bookmarks {
+		kind, _, err := player.ParseURI(b.URI)
+		if err != nil {
+			return fmt.Errorf("bookmark %d: %w", i+1, err)
+		}
+		if !bookmarkKinds[kind] {
+			return fmt.Errorf("bookmark %d: %s is a %s, not a playlist, album, artist or show", i+1, b.URI, kind)
+		}
+	}
+
+	return nil
+}
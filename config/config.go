@@ -5,48 +5,285 @@ import (
 	"encoding/json"
 	"github.com/joho/godotenv"
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"log"
+	"net/url"
+	"time"
 )
 
 func init() {
-    if err := godotenv.Load(); err != nil {
-        log.Fatal("Error loading .env file")
-    }
+	// .env is optional, credentials can also come from the environment,
+	// config.json or credentials.json. A missing file is fine; anything
+	// else is worth a warning but should not stop the program.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Ignoring .env: %v", err)
+	}
 }
 
+// MinPollInterval is the shortest poll_interval accepted
+const MinPollInterval = Duration(100 * time.Millisecond)
+
+// Backoff poll strategy defaults
+const (
+	// DefaultPollBackoffAfter is how many polls without playback pass
+	// before the backoff strategy slows down
+	DefaultPollBackoffAfter = 10
+	// DefaultPollBackoffMax is the longest interval of the backoff strategy
+	DefaultPollBackoffMax = Duration(time.Minute)
+)
+
+// ErrMissingCredentials is returned by Load when no client ID or secret is configured
+var ErrMissingCredentials = errors.New("client ID and secret must be provided")
+
 // Config holds the application configuration
 type Config struct {
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret"`
 	RedirectURI  string `json:"redirect_uri"`
 	TokenFile    string `json:"token_file"`
+
+	// UsePKCE logs in with PKCE, which needs no client secret
+	UsePKCE bool `json:"use_pkce"`
+
+	// RedirectURIs are fallbacks tried in order when the port of
+	// RedirectURI is busy during login
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+
+	// StatusFormat is the format string for the track info line.
+	// See player.CurrentlyPlaying.Format for the supported tokens.
+	StatusFormat string `json:"status_format"`
+	PlayingGlyph string `json:"playing_glyph"`
+	PausedGlyph  string `json:"paused_glyph"`
+
+	// ProgressBarWidth caps the width of the progress bar. Zero uses the
+	// full width of the pane.
+	ProgressBarWidth int `json:"progress_bar_width"`
+
+	// ShowAlbumArt draws the cover of the current track above the track
+	// info on terminals with at least 256 colors
+	ShowAlbumArt bool `json:"show_album_art"`
+
+	// ShowDetails adds the album release date and track popularity to the
+	// track info line
+	ShowDetails bool `json:"show_details"`
+
+	// ShowAudioFeatures adds the tempo and key of tracks to the track info line
+	ShowAudioFeatures bool `json:"show_audio_features"`
+
+	// PodcastSkipSeconds is the jump interval for the podcast skip keys
+	PodcastSkipSeconds int `json:"podcast_skip_seconds"`
+
+	// StopAtContextEnd pauses playback instead of letting Spotify's
+	// autoplay continue once a playlist, album or show has finished
+	StopAtContextEnd bool `json:"stop_at_context_end"`
+
+	// PersistStats keeps listening stats across runs in stats.json
+	PersistStats bool `json:"persist_stats"`
+
+	// IdlePause pauses playback after this long without any input in the
+	// player or any playback started elsewhere. Zero disables it.
+	IdlePause Duration `json:"idle_pause"`
+
+	// ConfirmDestructive asks before actions such as clearing the queue
+	ConfirmDestructive bool `json:"confirm_destructive"`
+
+	// MinimalScopes only asks for read access at login. Playback control
+	// is authorized the first time it is used.
+	MinimalScopes bool `json:"minimal_scopes"`
+
+	// CAFile is a PEM bundle of extra CAs to trust, e.g. for a TLS-inspecting proxy
+	CAFile string `json:"ca_file"`
+
+	// APIURL replaces the Spotify Web API base URL, e.g. to go through a
+	// debugging proxy. Empty uses Spotify's.
+	APIURL string `json:"api_url,omitempty"`
+
+	// PollStrategy is "fixed", "adaptive" or "backoff", see player.NewPoller
+	PollStrategy string `json:"poll_strategy"`
+
+	// PollBackoffAfter and PollBackoffMax tune the backoff strategy: after
+	// this many polls without playback the interval doubles up to the max
+	PollBackoffAfter int      `json:"poll_backoff_after"`
+	PollBackoffMax   Duration `json:"poll_backoff_max"`
+
+	// PollInterval is how often the playback state is fetched. The
+	// adaptive strategy uses it while playing and polls less otherwise.
+	PollInterval Duration `json:"poll_interval"`
+
+	// ErrorGrace is how long failing polls keep the last track info on
+	// screen before the error is shown
+	ErrorGrace Duration `json:"error_grace"`
+
+	// TrackEndRefresh is how long before the estimated end of the playing
+	// item an extra poll is made to pick up the next one. Zero disables it.
+	TrackEndRefresh Duration `json:"track_end_refresh"`
+
+	// Notifications shows a desktop notification when the track changes
+	Notifications bool `json:"notifications"`
+
+	// LyricsURL is the LRCLIB compatible API the lyrics panel uses
+	LyricsURL string `json:"lyrics_url"`
+
+	// LastFM enables scrobbling to Last.fm once all its keys are set
+	LastFM LastFM `json:"lastfm"`
+
+	// PauseOnExit pauses playback when the player is closed
+	PauseOnExit bool `json:"pause_on_exit"`
+
+	// ButtonLabels overrides the labels of the playback buttons.
+	// ASCIIButtons switches the defaults to plain ASCII for limited terminals.
+	ButtonLabels ButtonLabels `json:"button_labels"`
+	ASCIIButtons bool         `json:"ascii_buttons"`
+
+	// HideShortcuts starts with the shortcut hint row hidden
+	HideShortcuts bool `json:"hide_shortcuts"`
+
+	// Bookmarks are played with the number keys 1 to 9
+	Bookmarks []Bookmark `json:"bookmarks,omitempty"`
+
+	// RestoreVolume sets the active device to LastVolume on startup.
+	// LastVolume is written to the config file on exit while it is enabled.
+	RestoreVolume bool `json:"restore_volume"`
+	LastVolume    int  `json:"last_volume,omitempty"`
+
+	// Theme sets the colors of the UI
+	Theme Theme `json:"theme"`
+
+	// Keybindings maps action names to keys, replacing the default keys of
+	// those actions. See DefaultKeyBindings and unboundActions for the
+	// action names.
+	Keybindings map[string]string `json:"keybindings,omitempty"`
+
+	// ResumeOnStart offers to resume the item playing at the last exit
+	// when nothing is playing on startup
+	ResumeOnStart bool `json:"resume_on_start"`
+}
+
+// LastFM holds the Last.fm API account and the session key authorising it
+// for the user's Last.fm account
+type LastFM struct {
+	APIKey     string `json:"api_key,omitempty"`
+	APISecret  string `json:"api_secret,omitempty"`
+	SessionKey string `json:"session_key,omitempty"`
+}
+
+// Enabled reports whether all keys needed to scrobble are set
+func (l LastFM) Enabled() bool {
+	return l.APIKey != "" && l.APISecret != "" && l.SessionKey != ""
+}
+
+// Theme holds the UI colors, as names such as "green" or hex values such as
+// "#1db954". Unknown colors fall back to the defaults.
+type Theme struct {
+	AccentColor    string `json:"accent_color"`
+	ErrorColor     string `json:"error_color"`
+	ProgressFilled string `json:"progress_filled"`
+	ProgressEmpty  string `json:"progress_empty"`
 }
 
-// DefaultConfig returns a default configuration
+// ButtonLabels holds the labels of the playback buttons. Empty labels use
+// the defaults.
+type ButtonLabels struct {
+	Previous string `json:"previous,omitempty"`
+	Play     string `json:"play,omitempty"`
+	Pause    string `json:"pause,omitempty"`
+	Next     string `json:"next,omitempty"`
+}
+
+// DefaultConfig returns the built-in defaults, without reading the
+// environment or any file
 func DefaultConfig() Config {
-	homeDir, _ := os.UserHomeDir()
+	stateDir, _ := StateDir()
 	
 	return Config{
-		ClientID:     os.Getenv("CLIENT_ID"),
-		ClientSecret: os.Getenv("CLIENT_SECRET"),
 		RedirectURI:  "http://localhost:8080/callback",
-		TokenFile:    filepath.Join(homeDir, ".spotify-tmux", "token.json"),
+		TokenFile:    filepath.Join(stateDir, "token.json"),
+		StatusFormat: "{state} {artist} - {track} ({progress}/{duration})",
+		PlayingGlyph: "▶",
+		PausedGlyph:  "⏸",
+
+		PodcastSkipSeconds: 30,
+		ConfirmDestructive: true,
+		PollStrategy:       "fixed",
+		PollInterval:       Duration(time.Second),
+		ErrorGrace:         Duration(5 * time.Second),
+		TrackEndRefresh:    Duration(500 * time.Millisecond),
+		LyricsURL:          "https://lrclib.net/api",
+		PollBackoffAfter:   DefaultPollBackoffAfter,
+		PollBackoffMax:     DefaultPollBackoffMax,
+		Theme: Theme{
+			AccentColor:    "green",
+			ErrorColor:     "red",
+			ProgressFilled: "white",
+			ProgressEmpty:  "white",
+		},
+	}
+}
+
+// HasCredentials reports whether enough credentials are set to log in: a
+// client ID, and a client secret unless PKCE is used
+func (c Config) HasCredentials() bool {
+	return c.ClientID != "" && (c.ClientSecret != "" || c.UsePKCE)
+}
+
+// Path returns the path of the configuration file
+func Path() (string, error) {
+	configDir, err := Dir()
+	if err != nil {
+		return "", err
 	}
+	
+	return filepath.Join(configDir, "config.json"), nil
 }
 
-// Load loads the configuration from file or environment
+// LoadFile returns the defaults overlaid with the config file, if present,
+// without consulting the environment or validating the result
+func LoadFile() (Config, error) {
+	config := DefaultConfig()
+	
+	configFile, err := Path()
+	if err != nil {
+		return config, err
+	}
+	
+	data, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	
+	if err := json.Unmarshal(stripComments(data), &config); err != nil {
+		return config, fmt.Errorf("%s: %w", configFile, err)
+	}
+	
+	return config, nil
+}
+
+// Load loads the configuration, layering defaults, the .env variables,
+// config.json, credentials.json and the SPOTIFY_* environment variables
+// in that order
 func Load() (Config, error) {
 	config := DefaultConfig()
 	
+	// Variables from .env are the lowest-precedence source of credentials
+	if os.Getenv("CLIENT_ID") != "" {
+		config.ClientID = os.Getenv("CLIENT_ID")
+	}
+	
+	if os.Getenv("CLIENT_SECRET") != "" {
+		config.ClientSecret = os.Getenv("CLIENT_SECRET")
+	}
+	
 	// Try to load from file
-	homeDir, err := os.UserHomeDir()
+	configDir, err := Dir()
 	if err != nil {
 		return config, err
 	}
-	
-	configDir := filepath.Join(homeDir, ".spotify-tmux")
 	os.MkdirAll(configDir, 0755)
 	
 	configFile := filepath.Join(configDir, "config.json")
@@ -59,8 +296,22 @@ func Load() (Config, error) {
 			return config, err
 		}
 		
-		if err := json.Unmarshal(data, &config); err != nil {
-			return config, err
+		if err := json.Unmarshal(stripComments(data), &config); err != nil {
+			return config, fmt.Errorf("%s: %w", configFile, err)
+		}
+	}
+	
+	// The credentials file overrides config.json
+	creds, err := LoadCredentials()
+	if err != nil {
+		return config, err
+	}
+	if creds != nil {
+		if creds.ClientID != "" {
+			config.ClientID = creds.ClientID
+		}
+		if creds.ClientSecret != "" {
+			config.ClientSecret = creds.ClientSecret
 		}
 	}
 	
@@ -78,8 +329,66 @@ func Load() (Config, error) {
 	}
 	
 	// Validate configuration
-	if config.ClientID == "" || config.ClientSecret == "" {
-		return config, errors.New("client ID and secret must be provided")
+	if !config.HasCredentials() {
+		return config, ErrMissingCredentials
+	}
+	
+	if config.PodcastSkipSeconds <= 0 {
+		return config, errors.New("podcast_skip_seconds must be positive")
+	}
+	
+	if config.IdlePause < 0 {
+		return config, errors.New("idle_pause must not be negative")
+	}
+	
+	if config.ErrorGrace < 0 {
+		return config, errors.New("error_grace must not be negative")
+	}
+	
+	if config.APIURL != "" {
+		u, err := url.Parse(config.APIURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return config, fmt.Errorf("api_url %q must be an http or https URL", config.APIURL)
+		}
+	}
+	
+	if config.LyricsURL != "" {
+		u, err := url.Parse(config.LyricsURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return config, fmt.Errorf("lyrics_url %q must be an http or https URL", config.LyricsURL)
+		}
+	}
+	
+	if config.TrackEndRefresh < 0 {
+		return config, errors.New("track_end_refresh must not be negative")
+	}
+	
+	if config.ProgressBarWidth < 0 {
+		return config, errors.New("progress_bar_width must not be negative")
+	}
+	
+	if config.PollInterval < MinPollInterval {
+		return config, fmt.Errorf("poll_interval must be at least %s", time.Duration(MinPollInterval))
+	}
+	
+	if config.PollStrategy != "fixed" && config.PollStrategy != "adaptive" && config.PollStrategy != "backoff" {
+		return config, fmt.Errorf("poll_strategy must be \"fixed\", \"adaptive\" or \"backoff\", got %q", config.PollStrategy)
+	}
+	
+	if config.PollBackoffAfter < 0 {
+		return config, errors.New("poll_backoff_after must not be negative")
+	}
+	
+	if config.PollBackoffMax < config.PollInterval {
+		return config, errors.New("poll_backoff_max must not be shorter than poll_interval")
+	}
+	
+	if err := validateBookmarks(config.Bookmarks); err != nil {
+		return config, err
+	}
+	
+	if _, err := config.KeyBindings(); err != nil {
+		return config, err
 	}
 	
 	// Ensure token directory exists
@@ -93,22 +402,22 @@ func Load() (Config, error) {
 
 // Save saves the configuration to file
 func Save(config Config) error {
-	homeDir, err := os.UserHomeDir()
+	configDir, err := Dir()
 	if err != nil {
 		return err
 	}
 	
-	configDir := filepath.Join(homeDir, ".spotify-tmux")
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return err
 	}
 	
 	configFile := filepath.Join(configDir, "config.json")
 	
-	data, err := json.MarshalIndent(config, "", "  ")
+	data, err := json.MarshalIndent(newFileConfig(config), "", "  ")
 	if err != nil {
 		return err
 	}
 	
-	return os.WriteFile(configFile, data, 0644)
+	// The file may hold the client secret
+	return os.WriteFile(configFile, data, 0600)
 }
\ No newline at end of file
@@ -0,0 +1,73 @@
+// config/config_test.go
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSave(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+
+	config := DefaultConfig()
+	config.UsePKCE = true
+	if err := Save(config); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	path, _ := Path()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"client_id", "client_secret", "token_file"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("Save wrote %s", key)
+		}
+	}
+	if string(fields["use_pkce"]) != "true" {
+		t.Errorf("use_pkce = %s, want true", fields["use_pkce"])
+	}
+
+	// Settings that differ from the defaults are kept
+	config.ClientID = "id"
+	config.TokenFile = "/elsewhere/token.json"
+	if err := Save(config); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ClientID != "id" || loaded.TokenFile != "/elsewhere/token.json" || !loaded.UsePKCE {
+		t.Errorf("loaded %q, %q, use_pkce %v", loaded.ClientID, loaded.TokenFile, loaded.UsePKCE)
+	}
+}
+
+func TestHasCredentials(t *testing.T) {
+	tests := []struct {
+		id, secret string
+		pkce       bool
+		want       bool
+	}{
+		{"id", "secret", false, true},
+		{"id", "", true, true},
+		{"id", "", false, false},
+		{"", "secret", false, false},
+		{"", "", true, false},
+	}
+
+	for _, tt := range tests {
+		c := Config{ClientID: tt.id, ClientSecret: tt.secret, UsePKCE: tt.pkce}
+		if got := c.HasCredentials(); got != tt.want {
+			t.Errorf("HasCredentials() with %q, %q, pkce %v = %v, want %v", tt.id, tt.secret, tt.pkce, got, tt.want)
+		}
+	}
+}
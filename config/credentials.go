@@ -0,0 +1,87 @@
+// config/credentials.go
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Credentials holds the Spotify app credentials, kept apart from config.json
+// so the config can be shared without leaking the secret
+type Credentials struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// CredentialsPath returns the path of the credentials file
+func CredentialsPath() (string, error) {
+	configDir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "credentials.json"), nil
+}
+
+// LoadCredentials reads the credentials file. It returns nil credentials
+// when the file does not exist, and warns when it is readable by others.
+func LoadCredentials() (*Credentials, error) {
+	path, err := CredentialsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Unix permission bits mean nothing on Windows
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		log.Printf("Warning: %s is accessible by other users (mode %o), run: chmod 600 %s",
+			path, info.Mode().Perm(), path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &creds, nil
+}
+
+// SaveCredentials writes the credentials file with owner-only permissions
+func SaveCredentials(creds Credentials) error {
+	path, err := CredentialsPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, 0600)
+}
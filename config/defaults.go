@@ -0,0 +1,90 @@
+// config/defaults.go
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrConfigExists is returned by WriteDefault when the file already exists
+var ErrConfigExists = errors.New("config file already exists")
+
+// defaultHeader is written at the top of a freshly initialised config file
+const defaultHeader = `// spotify-tmux configuration
+//
+// Lines starting with // are comments and are ignored.
+// Credentials can also be supplied through the environment, which takes
+// precedence over this file:
+//   SPOTIFY_CLIENT_ID, SPOTIFY_CLIENT_SECRET, SPOTIFY_REDIRECT_URI
+//
+// client_id, client_secret: from https://developer.spotify.com/dashboard,
+//                           add them here or keep them in .env
+// redirect_uri:             must match a redirect URI registered for the app
+// token_file:               where the OAuth token is stored, add it to move
+//                           the token out of the config directory
+// status_format:            tokens {state} {artist} {track} {album}
+//                           {progress} {duration} {remaining}
+//                           {released} {popularity}
+// playing_glyph, paused_glyph: symbols used for {state}
+// podcast_skip_seconds:     jump interval of the [ and ] keys for episodes
+`
+
+// fileConfig is a configuration as written to config.json. It leaves out
+// empty credentials, which would override those from .env, and the default
+// token file, which Load already defaults.
+type fileConfig struct {
+	Config
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	TokenFile    string `json:"token_file,omitempty"`
+}
+
+// newFileConfig returns config as written to config.json
+func newFileConfig(config Config) fileConfig {
+	file := fileConfig{Config: config, ClientID: config.ClientID, ClientSecret: config.ClientSecret}
+	if config.TokenFile != DefaultConfig().TokenFile {
+		file.TokenFile = config.TokenFile
+	}
+	return file
+}
+
+// WriteDefault writes a commented configuration file holding the built-in
+// defaults to path. It refuses to overwrite an existing file unless force is set.
+func WriteDefault(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return ErrConfigExists
+	}
+
+	data, err := json.MarshalIndent(newFileConfig(DefaultConfig()), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(defaultHeader)
+	buf.Write(data)
+	buf.WriteString("\n")
+
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// stripComments removes whole-line // comments so the rest parses as JSON
+func stripComments(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "//") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return []byte(strings.Join(kept, "\n"))
+}
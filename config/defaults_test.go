@@ -0,0 +1,64 @@
+// config/defaults_test.go
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spotify-tmux", "config.json")
+	if err := WriteDefault(path, false); err != nil {
+		t.Fatalf("WriteDefault() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(stripComments(data), &fields); err != nil {
+		t.Fatalf("written config does not parse: %v", err)
+	}
+	for _, key := range []string{"client_id", "client_secret", "token_file"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("written config sets %s", key)
+		}
+	}
+	for _, key := range []string{"redirect_uri", "status_format", "podcast_skip_seconds"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("written config lacks %s", key)
+		}
+	}
+
+	// Credentials set before the file is applied must survive it
+	config := DefaultConfig()
+	config.ClientID = "from-env"
+	config.ClientSecret = "secret-from-env"
+	if err := json.Unmarshal(stripComments(data), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.ClientID != "from-env" || config.ClientSecret != "secret-from-env" {
+		t.Errorf("credentials = %q, %q after applying the file", config.ClientID, config.ClientSecret)
+	}
+	if want := DefaultConfig().TokenFile; config.TokenFile != want {
+		t.Errorf("TokenFile = %q, want %q", config.TokenFile, want)
+	}
+}
+
+func TestWriteDefaultExists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteDefault(path, false); !errors.Is(err, ErrConfigExists) {
+		t.Errorf("WriteDefault() error = %v, want ErrConfigExists", err)
+	}
+	if err := WriteDefault(path, true); err != nil {
+		t.Errorf("WriteDefault(force) error = %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+// config/keys.go
+package config
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// KeyBinding binds a key to a player action
+type KeyBinding struct {
+	Key    rune
+	Action string
+}
+
+// unboundActions are the actions without a default key, which can still be
+// bound in the config. The Web API cannot change the playback speed, so the
+// speed keys would only ever report that.
+var unboundActions = []string{"speed_down", "speed_up"}
+
+// DefaultKeyBindings returns the built-in bindings. Several keys may share
+// an action. Every action the player knows appears here, apart from
+// unboundActions.
+func DefaultKeyBindings() []KeyBinding {
+	return []KeyBinding{
+		{'p', "play_pause"}, {' ', "play_pause"},
+		{'n', "next"}, {'l', "next"},
+		{'b', "previous"}, {'h', "previous"},
+		{'B', "replay_last"},
+		{'r', "history"},
+		{'j', "seek_back"}, {'k', "seek_forward"},
+		{'-', "volume_down"}, {'+', "volume_up"},
+		{'[', "podcast_back"}, {']', "podcast_forward"},
+		{'C', "clear_queue"},
+		{'i', "pin"}, {'I', "unpin"},
+		{'E', "stop_at_end"},
+		{'a', "ab_loop"},
+		{'t', "seek_to"},
+		{'s', "like"},
+		{'x', "shuffle"},
+		{'R', "repeat"},
+		{'d', "devices"},
+		{'/', "search"},
+		{'u', "queue"},
+		{'y', "lyrics"},
+		{'L', "playlists"},
+		{'m', "stats"},
+		{'?', "help"},
+		{'H', "shortcuts"},
+		{'A', "login"},
+		{'q', "quit"},
+		{'1', "bookmark_1"}, {'2', "bookmark_2"}, {'3', "bookmark_3"},
+		{'4', "bookmark_4"}, {'5', "bookmark_5"}, {'6', "bookmark_6"},
+		{'7', "bookmark_7"}, {'8', "bookmark_8"}, {'9', "bookmark_9"},
+	}
+}
+
+// KeyBindings returns the default bindings with Keybindings applied. A
+// configured action loses its default keys and gets the one given, or none
+// if it is empty. It fails on unknown actions, keys that are not a single
+// character or "space", and keys bound to more than one action.
+func (c Config) KeyBindings() ([]KeyBinding, error) {
+	known := make(map[string]bool)
+	for _, binding := range DefaultKeyBindings() {
+		known[binding.Action] = true
+	}
+	for _, action := range unboundActions {
+		known[action] = true
+	}
+
+	// Sorted so that errors do not depend on map order
+	actions := make([]string, 0, len(c.Keybindings))
+	for action := range c.Keybindings {
+		if !known[action] {
+			return nil, fmt.Errorf("keybindings: unknown action %q", action)
+		}
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	var bindings []KeyBinding
+	for _, binding := range DefaultKeyBindings() {
+		if _, ok := c.Keybindings[binding.Action]; !ok {
+			bindings = append(bindings, binding)
+		}
+	}
+	for _, action := range actions {
+		name := c.Keybindings[action]
+		if name == "" {
+			continue
+		}
+		key, err := parseKey(name)
+		if err != nil {
+			return nil, fmt.Errorf("keybindings: %s: %w", action, err)
+		}
+		bindings = append(bindings, KeyBinding{Key: key, Action: action})
+	}
+
+	bound := make(map[rune]string)
+	for _, binding := range bindings {
+		if other, ok := bound[binding.Key]; ok {
+			return nil, fmt.Errorf("keybindings: %q is bound to both %s and %s", keyName(binding.Key), other, binding.Action)
+		}
+		bound[binding.Key] = binding.Action
+	}
+
+	return bindings, nil
+}
+
+// parseKey parses a key as written in the config file
+func parseKey(name string) (rune, error) {
+	if name == "space" {
+		return ' ', nil
+	}
+	if utf8.RuneCountInString(name) != 1 {
+		return 0, fmt.Errorf("%q is not a single key, use one character or \"space\"", name)
+	}
+	key, _ := utf8.DecodeRuneInString(name)
+	return key, nil
+}
+
+// keyName is how a key is written in the config file
+func keyName(key rune) string {
+	if key == ' ' {
+		return "space"
+	}
+	return string(key)
+}
@@ -0,0 +1,43 @@
+// config/keys_test.go
+package config
+
+import "testing"
+
+func TestDefaultKeyBindings(t *testing.T) {
+	actions := make(map[rune]string)
+	for _, binding := range DefaultKeyBindings() {
+		if other, ok := actions[binding.Key]; ok {
+			t.Errorf("key %q bound to %s and %s", binding.Key, other, binding.Action)
+		}
+		actions[binding.Key] = binding.Action
+	}
+
+	for key, want := range map[rune]string{'h': "previous", 'l': "next", 'n': "next", 's': "like"} {
+		if actions[key] != want {
+			t.Errorf("key %q bound to %q, want %q", key, actions[key], want)
+		}
+	}
+}
+
+func TestUnboundActions(t *testing.T) {
+	for _, binding := range DefaultKeyBindings() {
+		for _, action := range unboundActions {
+			if binding.Action == action {
+				t.Errorf("%s bound to %q by default", action, binding.Key)
+			}
+		}
+	}
+
+	c := Config{Keybindings: map[string]string{"speed_down": "<", "speed_up": ">"}}
+	bindings, err := c.KeyBindings()
+	if err != nil {
+		t.Fatalf("KeyBindings() error = %v", err)
+	}
+	bound := make(map[string]rune)
+	for _, binding := range bindings {
+		bound[binding.Action] = binding.Key
+	}
+	if bound["speed_down"] != '<' || bound["speed_up"] != '>' {
+		t.Errorf("speed keys = %q, %q, want < and >", bound["speed_down"], bound["speed_up"])
+	}
+}
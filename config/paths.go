@@ -0,0 +1,71 @@
+// config/paths.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// appName names the directories below the XDG base directories
+const appName = "spotify-tmux"
+
+// legacyDir returns ~/.spotify-tmux if it exists. Installations that
+// predate XDG support keep all their files there.
+func legacyDir() (string, bool) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+
+	dir := filepath.Join(homeDir, ".spotify-tmux")
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir, true
+	}
+	return "", false
+}
+
+// xdgDir returns $env/spotify-tmux, or fallback (relative to the home
+// directory)/spotify-tmux when the variable is unset or not absolute, as
+// the XDG Base Directory spec requires
+func xdgDir(env, fallback string) (string, error) {
+	if base := os.Getenv(env); filepath.IsAbs(base) {
+		return filepath.Join(base, appName), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, fallback, appName), nil
+}
+
+// Dir returns the directory holding the configuration file: ~/.spotify-tmux
+// if it exists, otherwise $XDG_CONFIG_HOME/spotify-tmux, which defaults
+// to ~/.config/spotify-tmux. A named profile uses profiles/NAME below it.
+func Dir() (string, error) {
+	return profileDir(baseDir())
+}
+
+// StateDir returns the directory holding the token and other state kept
+// between runs: ~/.spotify-tmux if it exists, otherwise
+// $XDG_STATE_HOME/spotify-tmux, which defaults to ~/.local/state/spotify-tmux.
+// A named profile uses profiles/NAME below it.
+func StateDir() (string, error) {
+	return profileDir(baseStateDir())
+}
+
+// baseDir returns the configuration directory of the default profile
+func baseDir() (string, error) {
+	if dir, ok := legacyDir(); ok {
+		return dir, nil
+	}
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// baseStateDir returns the state directory of the default profile
+func baseStateDir() (string, error) {
+	if dir, ok := legacyDir(); ok {
+		return dir, nil
+	}
+	return xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
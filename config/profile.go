@@ -0,0 +1,77 @@
+// config/profile.go
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// profilesDir holds the named profiles below the config and state directories
+const profilesDir = "profiles"
+
+// profileName is what a profile may be called, so it is always a single
+// plain directory name
+var profileName = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+// profile is the selected profile, empty for the default one
+var profile string
+
+// SetProfile selects the profile whose config and state are used for the
+// rest of the run. The default profile, selected by "", keeps its files
+// where they always were. Call it before loading anything.
+func SetProfile(name string) error {
+	if name != "" && !profileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q, use letters, digits, '.', '_' and '-'", name)
+	}
+	profile = name
+	return nil
+}
+
+// Profile returns the selected profile, empty for the default one
+func Profile() string {
+	return profile
+}
+
+// Profiles returns the names of the profiles that have a config or state
+// directory, sorted. The default profile is not included.
+func Profiles() ([]string, error) {
+	seen := make(map[string]bool)
+	for _, base := range []func() (string, error){baseDir, baseStateDir} {
+		dir, err := base()
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := os.ReadDir(filepath.Join(dir, profilesDir))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && profileName.MatchString(entry.Name()) {
+				seen[entry.Name()] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// profileDir returns the directory of the selected profile below dir
+func profileDir(dir string, err error) (string, error) {
+	if err != nil || profile == "" {
+		return dir, err
+	}
+	return filepath.Join(dir, profilesDir, profile), nil
+}
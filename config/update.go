@@ -0,0 +1,130 @@
+// config/update.go
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// UpdateFile sets one top-level setting in config.json and leaves the rest
+// of the file, comments included, as it is. The file is created if missing.
+// Unlike Save it never writes settings that were not in the file, which
+// could override the environment or the credentials file.
+func UpdateFile(key string, value interface{}) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(fmt.Sprintf("{\n  %q: %s\n}\n", key, encoded)), 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	updated, err := setKey(data, key, encoded)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Keep the mode, the file may hold the client secret
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, updated, info.Mode().Perm())
+}
+
+// setKey replaces the value of key in a config file, or adds the key before
+// the closing brace. Comment lines are never changed. Only scalar values
+// can be replaced, and the result must still parse.
+func setKey(data []byte, key string, value []byte) ([]byte, error) {
+	scalar := regexp.MustCompile(`("` + regexp.QuoteMeta(key) + `"\s*:\s*)(-?[0-9][0-9.eE+-]*|true|false|null|"(?:[^"\\]|\\.)*")`)
+
+	var updated []byte
+	lines := strings.SplitAfter(string(data), "\n")
+	for i, line := range lines {
+		if isComment([]byte(line)) {
+			continue
+		}
+		if loc := scalar.FindStringSubmatchIndex(line); loc != nil {
+			lines[i] = line[:loc[4]] + string(value) + line[loc[5]:]
+			updated = []byte(strings.Join(lines, ""))
+			break
+		}
+	}
+
+	if updated == nil {
+		brace := closingBrace(data)
+		if brace < 0 {
+			return nil, fmt.Errorf("no closing brace to add %s before", key)
+		}
+		at := lastContent(data, brace)
+		if at == 0 {
+			return nil, fmt.Errorf("no opening brace to add %s after", key)
+		}
+
+		entry := fmt.Sprintf("\n  %q: %s", key, value)
+		if data[at-1] != '{' {
+			entry = "," + entry
+		}
+		if data[at] != '\n' {
+			entry += "\n"
+		}
+		updated = append(append(append([]byte{}, data[:at]...), entry...), data[at:]...)
+	}
+
+	var check map[string]json.RawMessage
+	if err := json.Unmarshal(stripComments(updated), &check); err != nil {
+		return nil, fmt.Errorf("cannot set %s: %w", key, err)
+	}
+	return updated, nil
+}
+
+// isComment reports whether a line is a // comment
+func isComment(line []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(line), []byte("//"))
+}
+
+// closingBrace returns the offset of the last } outside comment lines, or -1
+func closingBrace(data []byte) int {
+	for end := len(data); end > 0; {
+		start := bytes.LastIndexByte(data[:end], '\n') + 1
+		if line := data[start:end]; !isComment(line) {
+			if i := bytes.LastIndexByte(line, '}'); i >= 0 {
+				return start + i
+			}
+		}
+		end = start - 1
+	}
+	return -1
+}
+
+// lastContent returns the offset just past the last byte before end that is
+// neither white space nor on a comment line
+func lastContent(data []byte, end int) int {
+	for end > 0 {
+		start := bytes.LastIndexByte(data[:end], '\n') + 1
+		if line := data[start:end]; !isComment(line) {
+			if trimmed := bytes.TrimRight(line, " \t\r"); len(trimmed) > 0 {
+				return start + len(trimmed)
+			}
+		}
+		end = start - 1
+	}
+	return 0
+}
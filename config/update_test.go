@@ -0,0 +1,121 @@
+// config/update_test.go
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetKey(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			"replace",
+			"{\n  // volume on exit\n  \"last_volume\": 30,\n  \"restore_volume\": true\n}\n",
+			"{\n  // volume on exit\n  \"last_volume\": 55,\n  \"restore_volume\": true\n}\n",
+		},
+		{
+			"comment mentions the key",
+			"{\n  // \"last_volume\": 10 is set on exit\n  \"last_volume\": 30\n}\n",
+			"{\n  // \"last_volume\": 10 is set on exit\n  \"last_volume\": 55\n}\n",
+		},
+		{
+			"add",
+			"// header\n{\n  \"restore_volume\": true\n}\n",
+			"// header\n{\n  \"restore_volume\": true,\n  \"last_volume\": 55\n}\n",
+		},
+		{
+			"add after a trailing comment",
+			"{\n  \"restore_volume\": true\n  // done\n}\n",
+			"{\n  \"restore_volume\": true,\n  \"last_volume\": 55\n  // done\n}\n",
+		},
+		{
+			"add to an empty object",
+			"{}",
+			"{\n  \"last_volume\": 55\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := setKey([]byte(tt.in), "last_volume", []byte("55"))
+			if err != nil {
+				t.Fatalf("setKey() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("setKey() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetKeyInvalid(t *testing.T) {
+	for _, in := range []string{"", "// only a comment\n", "{\n  \"a\": \n}\n"} {
+		if got, err := setKey([]byte(in), "last_volume", []byte("55")); err == nil {
+			t.Errorf("setKey(%q) = %q, want an error", in, got)
+		}
+	}
+}
+
+func TestUpdateFileKeepsOtherSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteDefault(path, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateFile("last_volume", 42); err != nil {
+		t.Fatalf("UpdateFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(stripComments(data), &fields); err != nil {
+		t.Fatal(err)
+	}
+	if string(fields["last_volume"]) != "42" {
+		t.Errorf("last_volume = %s, want 42", fields["last_volume"])
+	}
+	for _, key := range []string{"client_id", "client_secret", "token_file"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("UpdateFile wrote %s", key)
+		}
+	}
+	if data[0] != '/' {
+		t.Error("UpdateFile dropped the header comments")
+	}
+}
+
+func TestUpdateFileCreates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+
+	if err := UpdateFile("last_volume", 42); err != nil {
+		t.Fatalf("UpdateFile() error = %v", err)
+	}
+
+	config, err := LoadFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.LastVolume != 42 {
+		t.Errorf("LastVolume = %d, want 42", config.LastVolume)
+	}
+	if _, err := os.Stat(filepath.Join(home, appName, "config.json")); err != nil {
+		t.Error(err)
+	}
+}
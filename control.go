@@ -0,0 +1,81 @@
+// control.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mesyrob/spotify-tmux/auth"
+	"github.com/mesyrob/spotify-tmux/config"
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// controlActions are the playback subcommands, for binding tmux keys
+// without running the player
+var controlActions = map[string]func(*player.PlayerService) error{
+	"play":   (*player.PlayerService).Play,
+	"pause":  (*player.PlayerService).Pause,
+	"next":   (*player.PlayerService).Next,
+	"prev":   (*player.PlayerService).Previous,
+	"toggle": (*player.PlayerService).PlayPause,
+}
+
+// runControl implements the playback subcommands, e.g. `spotify-tmux next`
+func runControl(name string, args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "usage: spotify-tmux %s\n", name)
+		return 2
+	}
+
+	playerService, err := savedTokenPlayer()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if err := controlActions[name](playerService); err != nil {
+		switch {
+		case errors.Is(err, player.ErrNoActiveDevice):
+			fmt.Fprintln(os.Stderr, "No active device, start playing in a Spotify app first")
+		case errors.Is(err, player.ErrInsufficientScope):
+			fmt.Fprintf(os.Stderr, "The saved login lacks a permission, run spotify-tmux once to grant it\nPermissions by feature: %s\n", auth.ScopeHelp())
+		default:
+			fmt.Fprintf(os.Stderr, "Failed to %s: %v\n", name, err)
+		}
+		return 1
+	}
+	return 0
+}
+
+// cliRateLimitWait bounds the wait for rate limits in one-shot commands,
+// which tmux runs from status lines and key bindings
+const cliRateLimitWait = 2 * time.Second
+
+// savedTokenPlayer creates a player service from the configuration and the
+// saved token. Unlike setupServices it never starts the login flow, so it
+// is safe to use from tmux bindings.
+func savedTokenPlayer(opts ...player.Option) (*player.PlayerService, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	authService, err := newAuthService(cfg, extraAuthOptions...)
+	if err != nil {
+		return nil, err
+	}
+	token, err := authService.GetToken()
+	if err != nil {
+		return nil, fmt.Errorf("not logged in, run spotify-tmux once: %w", err)
+	}
+
+	opts = append([]player.Option{
+		player.WithStatusFormat(cfg.StatusFormat),
+		player.WithGlyphs(player.Glyphs{Playing: cfg.PlayingGlyph, Paused: cfg.PausedGlyph}),
+		player.WithBaseURL(cfg.APIURL),
+		player.WithRateLimitWait(cliRateLimitWait),
+	}, opts...)
+	return player.NewPlayerService(token, authService, opts...), nil
+}
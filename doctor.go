@@ -0,0 +1,207 @@
+// doctor.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mesyrob/spotify-tmux/auth"
+	"github.com/mesyrob/spotify-tmux/config"
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// check is the outcome of one doctor check
+type check struct {
+	name string
+	ok   bool
+	// detail describes the outcome, hint how to fix a failure
+	detail string
+	hint   string
+}
+
+// runDoctor implements `spotify-tmux doctor`. It checks each step needed
+// for the player to work and exits non-zero if any of them failed.
+func runDoctor(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "usage: spotify-tmux doctor")
+		return 2
+	}
+
+	checks := doctorChecks()
+
+	failed := false
+	for _, c := range checks {
+		status := " OK "
+		if !c.ok {
+			status = "FAIL"
+			failed = true
+		}
+		fmt.Printf("[%s] %-10s %s\n", status, c.name, c.detail)
+		if !c.ok && c.hint != "" {
+			fmt.Printf("       %-10s %s\n", "", c.hint)
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// doctorChecks runs the checks in order, stopping at the first failure
+// that makes the remaining checks meaningless
+func doctorChecks() []check {
+	var checks []check
+
+	// Configuration
+	cfg, err := config.Load()
+	if err != nil {
+		c := check{name: "Config", detail: err.Error()}
+		if errors.Is(err, config.ErrMissingCredentials) {
+			c.hint = "run `spotify-tmux setup` to enter the client ID and secret"
+		} else {
+			c.hint = "fix the config file or run `spotify-tmux config init -force`"
+		}
+		return append(checks, c)
+	}
+	checks = append(checks, check{name: "Config", ok: true, detail: "client credentials found"})
+
+	// Login redirect
+	checks = append(checks, checkRedirectURI(cfg.RedirectURI), checkCallbackPort(cfg))
+
+	// Token
+	authService, err := newAuthService(cfg)
+	if err != nil {
+		return append(checks, check{name: "Token", detail: err.Error(), hint: "check the ca_file setting"})
+	}
+	// An expired token is fine as long as it can be refreshed
+	token, err := authService.GetToken()
+	if err != nil {
+		c := check{name: "Token", detail: err.Error(), hint: "run `spotify-tmux` once to log in again"}
+		if errors.Is(err, auth.ErrNoToken) {
+			c.hint = fmt.Sprintf("run `spotify-tmux` once to log in; the token is saved to %s", cfg.TokenFile)
+		}
+		return append(checks, c)
+	}
+	checks = append(checks, check{name: "Token", ok: true, detail: "valid"})
+
+	playerService := player.NewPlayerService(token, authService,
+		player.WithBaseURL(cfg.APIURL), player.WithRateLimitWait(cliRateLimitWait))
+
+	// Connectivity
+	if err := playerService.Ping(); err != nil {
+		c := check{name: "API", detail: err.Error()}
+		var netErr *player.NetworkError
+		if errors.As(err, &netErr) {
+			c.hint = "check your internet connection and proxy settings"
+		} else {
+			c.hint = "the token may have been revoked, run `spotify-tmux` to log in again"
+		}
+		return append(checks, c)
+	}
+	checks = append(checks, check{name: "API", ok: true, detail: "reachable"})
+
+	// Device
+	devices, err := playerService.ListDevices()
+	switch {
+	case err != nil:
+		checks = append(checks, check{name: "Device", detail: err.Error()})
+	case player.ActiveDevice(devices) == nil:
+		checks = append(checks, check{name: "Device",
+			detail: fmt.Sprintf("no active device (%d available)", len(devices)),
+			hint:   "start playing something in a Spotify app to make it active"})
+	default:
+		checks = append(checks, check{name: "Device", ok: true,
+			detail: fmt.Sprintf("%s is active", player.ActiveDevice(devices).Name)})
+	}
+
+	// Product tier
+	profile, err := playerService.GetUserProfile()
+	switch {
+	case err != nil:
+		checks = append(checks, check{name: "Account", detail: err.Error()})
+	case profile.IsFree():
+		checks = append(checks, check{name: "Account", detail: "Spotify Free",
+			hint: "controlling playback requires Spotify Premium; the player runs read-only"})
+	case profile.Product == "":
+		checks = append(checks, check{name: "Account", ok: true,
+			detail: "tier unknown (token predates the user-read-private scope)"})
+	default:
+		checks = append(checks, check{name: "Account", ok: true, detail: "Spotify " + profile.Product})
+	}
+
+	return checks
+}
+
+// checkRedirectURI checks that the redirect URI can be served locally.
+// Spotify only redirects to it if it is registered for the app exactly as
+// written, which cannot be checked from here.
+func checkRedirectURI(uri string) check {
+	c := check{name: "Redirect", hint: "use e.g. http://127.0.0.1:8080/callback and register it exactly like that in the Spotify dashboard"}
+
+	u, err := url.Parse(uri)
+	switch {
+	case err != nil:
+		c.detail = err.Error()
+	case u.Scheme != "http":
+		c.detail = fmt.Sprintf("%s must use http, the login callback is served locally", uri)
+	case !isLoopback(u.Hostname()):
+		c.detail = fmt.Sprintf("%s does not point to this machine", uri)
+	default:
+		c.ok = true
+		c.detail = uri + " (must match the dashboard exactly)"
+	}
+	return c
+}
+
+// isLoopback reports whether host names this machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkCallbackPort checks that the login callback can listen on the port
+// of the redirect URI or of one of its fallbacks
+func checkCallbackPort(cfg config.Config) check {
+	c := check{name: "Port"}
+
+	var busy []string
+	for _, uri := range append([]string{cfg.RedirectURI}, cfg.RedirectURIs...) {
+		u, err := url.Parse(uri)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		addr := u.Host
+		if u.Port() == "" {
+			addr = net.JoinHostPort(u.Hostname(), "80")
+		}
+
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			busy = append(busy, addr)
+			continue
+		}
+		listener.Close()
+
+		c.ok = true
+		c.detail = addr + " is free"
+		if len(busy) > 0 {
+			c.detail += fmt.Sprintf(" (%s busy, the fallback is used)", strings.Join(busy, ", "))
+		}
+		return c
+	}
+
+	c.detail = "no usable port for the login callback"
+	if len(busy) > 0 {
+		c.detail = strings.Join(busy, ", ") + " in use"
+	}
+	c.hint = "stop the program using the port, or add a free one to redirect_uris (and the dashboard)"
+	return c
+}
@@ -0,0 +1,97 @@
+// export.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// runExport implements `spotify-tmux export`, which writes the queue or a
+// playlist's tracks as JSON or "Artist - Title" lines
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "text", "output format: json or text")
+	playlist := flags.String("playlist", "", "playlist URI or ID to export instead of the queue")
+	output := flags.String("o", "", "file to write to (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format != "json" && *format != "text" {
+		fmt.Fprintf(os.Stderr, "Unknown format %q, use json or text\n", *format)
+		return 2
+	}
+
+	_, _, playerService, err := setupServices()
+	if err != nil {
+		printSetupError(err)
+		return 1
+	}
+
+	var tracks []player.Track
+	if *playlist != "" {
+		id := *playlist
+		if strings.HasPrefix(id, "spotify:") {
+			kind, parsed, err := player.ParseURI(id)
+			if err != nil || kind != "playlist" {
+				fmt.Fprintf(os.Stderr, "%s is not a playlist URI\n", id)
+				return 2
+			}
+			id = parsed
+		}
+
+		tracks, err = playerService.GetAllPlaylistTracks(id)
+	} else {
+		var queue *player.Queue
+		queue, err = playerService.GetQueue()
+		if err == nil {
+			tracks = queue.QueueItems
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to fetch tracks: %v\n", err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", *output, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := writeTracks(w, tracks, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write tracks: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeTracks writes tracks as a JSON array or as "Artist - Title" lines
+func writeTracks(w io.Writer, tracks []player.Track, format string) error {
+	if format == "json" {
+		if tracks == nil {
+			tracks = []player.Track{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tracks)
+	}
+
+	for _, track := range tracks {
+		if _, err := fmt.Fprintln(w, track.DisplayName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
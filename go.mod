@@ -4,13 +4,13 @@ go 1.23.6
 
 require (
 	github.com/gdamore/tcell/v2 v2.8.1
+	github.com/joho/godotenv v1.5.1
 	github.com/rivo/tview v0.0.0-20241227133733-17b7edb88c57
 	golang.org/x/oauth2 v0.27.0
 )
 
 require (
 	github.com/gdamore/encoding v1.0.1 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
@@ -0,0 +1,81 @@
+// lyrics/lyrics.go
+package lyrics
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Track identifies the song to look up
+type Track struct {
+	Artist   string
+	Title    string
+	Album    string
+	Duration time.Duration
+}
+
+// Line is a line of synced lyrics
+type Line struct {
+	// At is when the line starts
+	At   time.Duration
+	Text string
+}
+
+// Lyrics are the lyrics of a song. Lines is only set when they are synced
+// to the song, Plain always.
+type Lyrics struct {
+	Plain string
+	Lines []Line
+}
+
+// ErrNotFound is returned by providers that have no lyrics for a track
+var ErrNotFound = errors.New("lyrics not found")
+
+// Provider looks up lyrics
+type Provider interface {
+	Lookup(track Track) (*Lyrics, error)
+}
+
+// lrcTimestamp matches the timestamps starting a line of LRC lyrics,
+// e.g. "[01:02.34]"
+var lrcTimestamp = regexp.MustCompile(`^\[(\d+):(\d{1,2}(?:\.\d+)?)\]`)
+
+// ParseLRC parses synced lyrics in LRC format, one "[mm:ss.xx] text" line
+// each. A line may start with several timestamps. Metadata tags and lines
+// without a timestamp are skipped. The lines are returned in time order.
+func ParseLRC(lrc string) []Line {
+	var lines []Line
+	for _, raw := range strings.Split(lrc, "\n") {
+		raw = strings.TrimSpace(raw)
+
+		var stamps []time.Duration
+		for {
+			m := lrcTimestamp.FindStringSubmatch(raw)
+			if m == nil {
+				break
+			}
+			minutes, _ := strconv.Atoi(m[1])
+			seconds, _ := strconv.ParseFloat(m[2], 64)
+			stamps = append(stamps, time.Duration(minutes)*time.Minute+time.Duration(seconds*float64(time.Second)))
+			raw = raw[len(m[0]):]
+		}
+
+		text := strings.TrimSpace(raw)
+		for _, at := range stamps {
+			lines = append(lines, Line{At: at, Text: text})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].At < lines[j].At })
+	return lines
+}
+
+// LineAt returns the index of the line sung at position, or -1 before the
+// first line or for unsynced lyrics
+func (l *Lyrics) LineAt(position time.Duration) int {
+	return sort.Search(len(l.Lines), func(i int) bool { return l.Lines[i].At > position }) - 1
+}
@@ -2,11 +2,15 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 	
 	"github.com/mesyrob/spotify-tmux/auth"
 	"github.com/mesyrob/spotify-tmux/config"
@@ -15,43 +19,268 @@ import (
 )
 
 func main() {
+	tokenStdin := flag.Bool("token-stdin", false, "read the token JSON from stdin and keep it in memory instead of the token file")
+	printToken := flag.Bool("print-token", false, "print the current token JSON and exit")
+	logout := flag.Bool("logout", false, "delete the saved token and exit")
+	status := flag.Bool("status", false, "print the current track on one line and exit, e.g. for the tmux status bar")
+	format := flag.String("format", "", "status format used by -status, defaults to status_format")
+	jsonOut := flag.Bool("json", false, "print the playback state as JSON and exit")
+	profile := flag.String("profile", "", "use the config and login of a named profile, e.g. for a second account")
+	flag.Parse()
+	
+	if err := config.SetProfile(*profile); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	
+	if *logout {
+		os.Exit(runLogout())
+	}
+	
+	if *tokenStdin {
+		info, err := auth.ReadTokenInfo(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read token from stdin: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Using token %s from stdin\n", auth.Redact(info.Token.AccessToken))
+		extraAuthOptions = append(extraAuthOptions, auth.WithTokenStore(auth.NewMemoryTokenStore(info)))
+	}
+	
+	if *status {
+		os.Exit(runStatus(*format))
+	}
+	if *jsonOut {
+		os.Exit(runJSON())
+	}
+	
+	// Run a subcommand if one was given
+	if flag.NArg() > 0 {
+		if code, ok := runCommand(flag.Args()); ok {
+			os.Exit(code)
+		}
+	}
+	
+	// Load configuration and authenticate
+	cfg, authService, playerService, err := setupServices()
+	if err != nil {
+		printSetupError(err)
+		os.Exit(1)
+	}
+	
+	if *printToken {
+		os.Exit(runPrintToken(authService))
+	}
+	
+	// Renew the token before it expires for as long as the player runs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	authService.StartTokenRefresher(ctx)
+	
+	// Initialize UI
+	userInterface := ui.NewUI(playerService, cfg)
+	playerService.SetWarningHandler(userInterface.ShowWarning)
+	if !authService.HasScopes(auth.ControlScopes...) {
+		userInterface.SetControlGrant(func() error {
+			return authService.RequestScopes(auth.ControlScopes...)
+		})
+	}
+	if !authService.HasScopes(auth.LibraryScopes...) {
+		userInterface.SetLibraryGrant(func() error {
+			return authService.RequestScopes(auth.LibraryScopes...)
+		})
+	}
+	if !authService.HasScopes(auth.HistoryScopes...) {
+		userInterface.SetHistoryGrant(func() error {
+			return authService.RequestScopes(auth.HistoryScopes...)
+		})
+	}
+	if !authService.HasScopes(auth.PlaylistScopes...) {
+		userInterface.SetPlaylistGrant(func() error {
+			return authService.RequestScopes(auth.PlaylistScopes...)
+		})
+	}
+	
+	userInterface.SetReauth(func() error {
+		if err := authService.Authenticate(); err != nil {
+			return err
+		}
+		playerService.RefreshClient()
+		return nil
+	})
+	
+	// Start the UI
+	done := make(chan struct{})
+	go func() {
+		userInterface.Start()
+		close(done)
+	}()
+	
+	// Handle graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	
+	select {
+	case <-sigChan:
+		fmt.Println("\nShutting down...")
+		userInterface.Stop()
+		
+		// Give the UI a moment to save its state
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+		}
+	case <-done:
+		// The UI was closed from within
+	}
+	
+	if cfg.PauseOnExit {
+		pauseOnExit(playerService)
+	}
+}
+
+// pauseOnExitTimeout bounds the final pause so a hung request cannot block exit
+const pauseOnExitTimeout = 2 * time.Second
+
+// pauseOnExit pauses playback, giving up after pauseOnExitTimeout
+func pauseOnExit(playerService *player.PlayerService) {
+	result := make(chan error, 1)
+	go func() {
+		current, err := playerService.GetCurrentlyPlaying()
+		if err != nil || !current.IsPlaying {
+			result <- err
+			return
+		}
+		result <- playerService.Pause()
+	}()
+	
+	select {
+	case err := <-result:
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to pause playback: %v\n", err)
+		}
+	case <-time.After(pauseOnExitTimeout):
+		fmt.Fprintln(os.Stderr, "Failed to pause playback: timed out")
+	}
+}
+
+// newAuthService creates the auth service for a configuration, using PKCE
+// when use_pkce is set
+func newAuthService(cfg config.Config, opts ...auth.Option) (*auth.AuthService, error) {
+	transport, err := auth.NewTransport(cfg.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up HTTP transport: %w", err)
+	}
+	
+	opts = append([]auth.Option{
+		auth.WithTransport(transport),
+		auth.WithFallbackRedirectURIs(cfg.RedirectURIs),
+		auth.WithTokenFile(cfg.TokenFile),
+	}, opts...)
+	
+	if cfg.UsePKCE {
+		return auth.NewAuthServicePKCE(cfg.ClientID, cfg.RedirectURI, opts...), nil
+	}
+	return auth.NewAuthService(cfg.ClientID, cfg.ClientSecret, cfg.RedirectURI, opts...), nil
+}
+
+// extraAuthOptions are applied by setupServices on top of those from the
+// configuration, e.g. to keep the token in memory
+var extraAuthOptions []auth.Option
+
+// setupServices loads the configuration, authenticates if needed and
+// creates the player service
+func setupServices() (config.Config, *auth.AuthService, *player.PlayerService, error) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		return cfg, nil, nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	// Initialize auth service
-	authService := auth.NewAuthService(cfg.ClientID, cfg.ClientSecret, cfg.RedirectURI)
+	// Initialize auth service, with only the read scopes up front if
+	// minimal_scopes is set
+	scopes := auth.AllScopes()
+	if cfg.MinimalScopes {
+		scopes = auth.ReadScopes
+	}
+	authService, err := newAuthService(cfg, append([]auth.Option{auth.WithScopes(scopes)}, extraAuthOptions...)...)
+	if err != nil {
+		return cfg, nil, nil, err
+	}
 	
-	// Check if we need to authenticate
-	if !authService.HasValidToken() {
+	// Check if we need to authenticate. An expired token is refreshed here,
+	// and only a missing or unrefreshable one starts the login flow.
+	_, err = authService.GetToken()
+	switch {
+	case errors.Is(err, auth.ErrNoToken):
 		fmt.Println("No valid token found. Starting authentication flow...")
+	case errors.Is(err, auth.ErrReauthRequired):
+		fmt.Printf("Spotify rejected the saved login (%v).\nStarting authentication flow...\n", err)
+	case err != nil:
+		return cfg, nil, nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if err != nil {
 		if err := authService.Authenticate(); err != nil {
-			log.Fatalf("Authentication failed: %v", err)
+			return cfg, nil, nil, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	
+	// A token obtained with minimal_scopes lacks the control scopes
+	if !cfg.MinimalScopes && !authService.HasScopes(auth.ControlScopes...) {
+		fmt.Println("Playback control needs additional permissions. Starting authentication flow...")
+		if err := authService.RequestScopes(auth.ControlScopes...); err != nil {
+			return cfg, nil, nil, fmt.Errorf("authentication failed: %w", err)
 		}
 	}
 	
 	// Get the token
 	token, err := authService.GetToken()
 	if err != nil {
-		log.Fatalf("Failed to get token: %v", err)
+		return cfg, nil, nil, fmt.Errorf("failed to get token: %w", err)
 	}
 	
 	// Initialize player service
-	playerService := player.NewPlayerService(token, authService)
+	playerService := player.NewPlayerService(token, authService,
+		player.WithStatusFormat(cfg.StatusFormat),
+		player.WithGlyphs(player.Glyphs{Playing: cfg.PlayingGlyph, Paused: cfg.PausedGlyph}),
+		player.WithBaseURL(cfg.APIURL),
+	)
 	
-	// Initialize UI
-	userInterface := ui.NewUI(playerService)
+	return cfg, authService, playerService, nil
+}
+// runLogout deletes the saved token so the next start logs in again
+func runLogout() int {
+	// Logging out must work even when the credentials are missing
+	cfg, _ := config.Load()
 	
-	// Start the UI
-	go userInterface.Start()
+	authService, err := newAuthService(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := authService.Logout(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to log out: %v\n", err)
+		return 1
+	}
 	
-	// Handle graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	fmt.Println("Logged out. The next start will ask you to log in again.")
+	return 0
+}
+
+// runPrintToken prints the current token JSON, refreshed if it had expired
+func runPrintToken(authService *auth.AuthService) int {
+	info, err := authService.TokenInfo()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get token: %v\n", err)
+		return 1
+	}
+	
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode token: %v\n", err)
+		return 1
+	}
 	
-	<-sigChan
-	fmt.Println("\nShutting down...")
-	userInterface.Stop()
-}
\ No newline at end of file
+	fmt.Println(string(data))
+	return 0
+}
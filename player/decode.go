@@ -0,0 +1,71 @@
+// player/decode.go
+package player
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// DecodeWarning reports a response field whose type no longer matches what
+// the player expects. The rest of the response was decoded, so the result is
+// usable but incomplete.
+type DecodeWarning struct {
+	Err *json.UnmarshalTypeError
+}
+
+// Error implements the error interface
+func (w *DecodeWarning) Error() string {
+	field := w.Err.Field
+	if field == "" {
+		field = "response"
+	}
+	return fmt.Sprintf("unexpected %s for %s in Spotify's response, some details may be missing", w.Err.Value, field)
+}
+
+// Unwrap returns the underlying decoding error
+func (w *DecodeWarning) Unwrap() error {
+	return w.Err
+}
+
+// decodeJSON decodes data into v, tolerating fields whose type changed.
+// encoding/json skips such fields and decodes everything else, so a type
+// mismatch is reported as a *DecodeWarning alongside the partial result.
+// Malformed JSON is still an error.
+func decodeJSON(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &DecodeWarning{Err: typeErr}
+	}
+	return err
+}
+
+// SetWarningHandler sets a function called with problems that did not stop
+// a request from succeeding, such as a *DecodeWarning. It may be called from
+// any goroutine.
+func (p *PlayerService) SetWarningHandler(handler func(error)) {
+	p.warnMu.Lock()
+	defer p.warnMu.Unlock()
+	p.warnHandler = handler
+}
+
+// decode decodes a response body with decodeJSON, passing warnings to the
+// warning handler instead of failing
+func (p *PlayerService) decode(data []byte, v interface{}) error {
+	err := decodeJSON(data, v)
+
+	var warning *DecodeWarning
+	if !errors.As(err, &warning) {
+		return err
+	}
+
+	p.warnMu.Lock()
+	handler := p.warnHandler
+	p.warnMu.Unlock()
+	if handler != nil {
+		handler(warning)
+	}
+	return nil
+}
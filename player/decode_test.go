@@ -0,0 +1,129 @@
+// player/decode_test.go
+package player
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fixture reads a response body from testdata
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDecodeNumbersAsStrings(t *testing.T) {
+	var current CurrentlyPlaying
+	err := decodeJSON(fixture(t, "playing_string_numbers.json"), &current)
+
+	var warning *DecodeWarning
+	if !errors.As(err, &warning) {
+		t.Fatalf("decodeJSON() error = %v, want a *DecodeWarning", err)
+	}
+	if warning.Err.Field != "progress_ms" || warning.Err.Value != "string" {
+		t.Errorf("warning for %s %s, want the first mismatch, string progress_ms", warning.Err.Value, warning.Err.Field)
+	}
+
+	// The mismatched fields stay zero, everything else is decoded
+	if current.Progress != 0 || current.Track.Duration != 0 {
+		t.Errorf("progress %d of %d, want both zero", current.Progress, current.Track.Duration)
+	}
+	if !current.IsPlaying || current.Timestamp != 1700000000000 || current.Type != "track" {
+		t.Errorf("IsPlaying = %v, Timestamp = %d, Type = %q", current.IsPlaying, current.Timestamp, current.Type)
+	}
+	if current.Track.Name != "Song" || current.Track.URI != "spotify:track:abc" || current.Track.Popularity != 55 {
+		t.Errorf("track = %+v", current.Track)
+	}
+	if len(current.Track.Artists) != 1 || current.Track.Artists[0].Name != "Artist" {
+		t.Errorf("artists = %+v", current.Track.Artists)
+	}
+	if got := current.Track.Album.Released(); got != "15 Dec 1981" {
+		t.Errorf("Released() = %q, want 15 Dec 1981", got)
+	}
+}
+
+func TestDecodeStringsAsNumbers(t *testing.T) {
+	var current CurrentlyPlaying
+	err := decodeJSON(fixture(t, "playing_number_strings.json"), &current)
+
+	var warning *DecodeWarning
+	if !errors.As(err, &warning) {
+		t.Fatalf("decodeJSON() error = %v, want a *DecodeWarning", err)
+	}
+	if warning.Err.Field != "item.name" || warning.Err.Value != "number" {
+		t.Errorf("warning for %s %s, want number item.name", warning.Err.Value, warning.Err.Field)
+	}
+
+	if current.Track.Name != "" || current.Track.Album.Name != "" {
+		t.Errorf("names %q, %q, want both empty", current.Track.Name, current.Track.Album.Name)
+	}
+	if current.IsPlaying || current.Progress != 1000 || current.Track.Duration != 200000 {
+		t.Errorf("IsPlaying = %v, progress %d of %d", current.IsPlaying, current.Progress, current.Track.Duration)
+	}
+	if current.Track.URI != "spotify:track:abc" || current.Track.Popularity != 40 {
+		t.Errorf("track = %+v", current.Track)
+	}
+}
+
+func TestDecodeExtraFields(t *testing.T) {
+	var current CurrentlyPlaying
+	if err := decodeJSON(fixture(t, "playing_extra_fields.json"), &current); err != nil {
+		t.Fatalf("decodeJSON() error = %v", err)
+	}
+
+	if !current.IsPlaying || current.Progress != 5000 || current.Track.Duration != 180000 {
+		t.Errorf("IsPlaying = %v, progress %d of %d", current.IsPlaying, current.Progress, current.Track.Duration)
+	}
+	if current.Track.Name != "Song" || current.Track.Album.Name != "Album" {
+		t.Errorf("track = %+v", current.Track)
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	var current CurrentlyPlaying
+	err := decodeJSON(fixture(t, "playing_malformed.json"), &current)
+
+	var warning *DecodeWarning
+	if err == nil || errors.As(err, &warning) {
+		t.Errorf("decodeJSON() error = %v, want a hard error", err)
+	}
+}
+
+func TestGetCurrentlyPlayingWarns(t *testing.T) {
+	body := fixture(t, "playing_string_numbers.json")
+	p := newTestPlayer(t, &scripted{replies: []reply{{status: 200, body: string(body)}}})
+
+	var warnings []error
+	p.SetWarningHandler(func(err error) { warnings = append(warnings, err) })
+
+	current, err := p.GetCurrentlyPlaying()
+	if err != nil {
+		t.Fatalf("GetCurrentlyPlaying() error = %v", err)
+	}
+	if current.Track.Name != "Song" {
+		t.Errorf("track = %q, want the partial result", current.Track.Name)
+	}
+	if string(current.Raw) != string(body) {
+		t.Error("Raw does not hold the full response")
+	}
+
+	var warning *DecodeWarning
+	if len(warnings) != 1 || !errors.As(warnings[0], &warning) {
+		t.Errorf("warnings = %v, want one *DecodeWarning", warnings)
+	}
+}
+
+func TestGetCurrentlyPlayingMalformed(t *testing.T) {
+	body := fixture(t, "playing_malformed.json")
+	p := newTestPlayer(t, &scripted{replies: []reply{{status: 200, body: string(body)}}})
+
+	if _, err := p.GetCurrentlyPlaying(); err == nil {
+		t.Error("GetCurrentlyPlaying() succeeded on malformed JSON")
+	}
+}
@@ -0,0 +1,77 @@
+// player/device.go
+package player
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Device represents a Spotify Connect device
+type Device struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	IsActive     bool   `json:"is_active"`
+	IsRestricted bool   `json:"is_restricted"`
+	// VolumePercent is nil for devices without volume control
+	VolumePercent *int `json:"volume_percent"`
+}
+
+// ListDevices lists the user's available Spotify Connect devices
+func (p *PlayerService) ListDevices() ([]Device, error) {
+	var result struct {
+		Devices []Device `json:"devices"`
+	}
+	if _, err := p.getJSON("/me/player/devices", nil, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Devices, nil
+}
+
+// ActiveDevice returns the active device, or nil if there is none
+func ActiveDevice(devices []Device) *Device {
+	for i := range devices {
+		if devices[i].IsActive {
+			return &devices[i]
+		}
+	}
+	return nil
+}
+
+// TransferPlayback moves playback to a device. If play is set playback
+// starts there, otherwise the current playing state is kept.
+func (p *PlayerService) TransferPlayback(deviceID string, play bool) error {
+	if deviceID == "" {
+		return errors.New("no device given")
+	}
+
+	data, err := json.Marshal(struct {
+		DeviceIDs []string `json:"device_ids"`
+		Play      bool     `json:"play"`
+	}{[]string{deviceID}, play})
+	if err != nil {
+		return err
+	}
+
+	return p.send("PUT", "/me/player", bytes.NewReader(data), nil)
+}
+
+// SetVolume sets the volume of the active device, from 0 to 100
+func (p *PlayerService) SetVolume(percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("volume must be between 0 and 100, got %d", percent)
+	}
+
+	query := url.Values{"volume_percent": {strconv.Itoa(percent)}}
+	return p.send("PUT", "/me/player/volume", nil, query)
+}
+
+// Ping checks that the Web API is reachable and accepts the token
+func (p *PlayerService) Ping() error {
+	return p.send("GET", "/me", nil, nil)
+}
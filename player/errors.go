@@ -0,0 +1,117 @@
+// player/errors.go
+package player
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// APIError is returned when Spotify answers a request with an error status
+type APIError struct {
+	StatusCode int
+	Status     string
+	// Message is Spotify's error message, or the raw body if it had none
+	Message string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s, %s", e.Status, e.Message)
+}
+
+// Errors matched by API errors with the corresponding status code
+var (
+	// ErrUnauthorized matches 401 responses: the token was rejected even
+	// after a retry and the user has to log in again
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches 403 responses, e.g. missing scopes or actions
+	// that need Spotify Premium
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited matches 429 responses that persisted after retrying
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// ErrInsufficientScope matches API errors caused by a token that lacks the
+// scope an endpoint needs
+var ErrInsufficientScope = errors.New("insufficient scope")
+
+// ErrNoActiveDevice matches API errors caused by there being no active
+// device to control, e.g. from Play or Pause. Callers can offer to pick a
+// device with ListDevices and TransferPlayback.
+var ErrNoActiveDevice = errors.New("no active device")
+
+// Is reports whether the error is of one of the kinds above, so that e.g.
+// errors.Is(err, ErrUnauthorized) or errors.Is(err, ErrNoActiveDevice) can
+// be used
+func (e *APIError) Is(target error) bool {
+	message := strings.ToLower(e.Message)
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrInsufficientScope:
+		return e.StatusCode == http.StatusForbidden && strings.Contains(message, "scope")
+	case ErrNoActiveDevice:
+		return e.StatusCode == http.StatusNotFound && strings.Contains(message, "no active device")
+	}
+	return false
+}
+
+// NetworkError is returned when a request did not reach Spotify at all,
+// e.g. on DNS failures, refused connections or timeouts
+type NetworkError struct {
+	Err error
+}
+
+// Error implements the error interface
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("network error: %v", e.Err)
+}
+
+// Unwrap returns the underlying transport error
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError builds an APIError from an error response
+func newAPIError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Message:    string(body),
+	}
+
+	// Spotify errors look like {"error": {"status": 404, "message": "..."}}
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Error.Message != "" {
+		apiErr.Message = payload.Error.Message
+	}
+
+	return apiErr
+}
+
+// wrapNetworkError wraps an error returned by the HTTP client in a
+// NetworkError. Token refresh failures surface through the client too, but
+// they are answers from Spotify's auth server and are returned unchanged.
+func wrapNetworkError(err error) error {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return err
+	}
+	return &NetworkError{Err: err}
+}
@@ -0,0 +1,102 @@
+// player/events.go
+package player
+
+import "time"
+
+// ChangeReason describes why the playing item changed
+type ChangeReason string
+
+const (
+	// ChangeUnknown is used when there is not enough information to tell
+	ChangeUnknown ChangeReason = "unknown"
+	// ChangeAdvance means the previous item played to its end
+	ChangeAdvance ChangeReason = "advance"
+	// ChangeSkip means the previous item was left before its end
+	ChangeSkip ChangeReason = "skip"
+	// ChangeContext means playback moved to a different playlist, album or show
+	ChangeContext ChangeReason = "context"
+)
+
+// advanceTolerance is how close to the end an item must have been when it
+// changed for the change to count as a natural advance
+const advanceTolerance = 3 * time.Second
+
+// TrackChange is the payload describing a change of the playing item
+type TrackChange struct {
+	Previous *CurrentlyPlaying `json:"previous"`
+	Current  *CurrentlyPlaying `json:"current"`
+	Reason   ChangeReason      `json:"reason"`
+
+	// PlayedToEnd is set when the previous item is estimated to have
+	// finished on its own, whatever the reason. A context change with
+	// PlayedToEnd set is how Spotify's autoplay shows up.
+	PlayedToEnd bool `json:"played_to_end"`
+}
+
+// DetectChange compares two consecutive polls and reports whether the
+// playing item changed, and why.
+//
+// The reason is inferred conservatively:
+//   - a different context URI is a context change
+//   - otherwise the moment of the switch is estimated from the new item's
+//     progress, and the previous item's position at that moment is
+//     extrapolated from its last poll. If that position is within a few
+//     seconds of its duration it was a natural advance, otherwise a skip
+//   - anything that cannot be estimated (unknown duration, paused previous
+//     item, missing fetch times) is reported as unknown
+func DetectChange(prev, cur *CurrentlyPlaying) (TrackChange, bool) {
+	if prev == nil || cur == nil || prev.Track.URI == cur.Track.URI {
+		return TrackChange{}, false
+	}
+
+	change := TrackChange{Previous: prev, Current: cur, Reason: ChangeUnknown}
+
+	// Nothing was playing before, so there is nothing to compare against
+	if prev.Track.URI == "" {
+		return change, true
+	}
+
+	finished, known := playedToEnd(prev, cur)
+	change.PlayedToEnd = finished
+
+	if contextURI(prev) != contextURI(cur) {
+		change.Reason = ChangeContext
+		return change, true
+	}
+
+	if !known {
+		return change, true
+	}
+
+	if finished {
+		change.Reason = ChangeAdvance
+	} else {
+		change.Reason = ChangeSkip
+	}
+
+	return change, true
+}
+
+// playedToEnd estimates whether prev finished on its own before cur started.
+// known is false when there is not enough information to tell.
+func playedToEnd(prev, cur *CurrentlyPlaying) (finished, known bool) {
+	if prev.Track.Duration <= 0 || !prev.IsPlaying || prev.FetchedAt.IsZero() || cur.FetchedAt.IsZero() {
+		return false, false
+	}
+
+	switchedAt := cur.FetchedAt.Add(-time.Duration(cur.Progress) * time.Millisecond)
+	played := time.Duration(prev.Progress)*time.Millisecond + switchedAt.Sub(prev.FetchedAt)
+	if played < 0 {
+		return false, false
+	}
+
+	return time.Duration(prev.Track.Duration)*time.Millisecond-played <= advanceTolerance, true
+}
+
+// contextURI returns the context URI of a playback state, if any
+func contextURI(c *CurrentlyPlaying) string {
+	if c.Context == nil {
+		return ""
+	}
+	return c.Context.URI
+}
@@ -0,0 +1,65 @@
+// player/features.go
+package player
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// AudioFeatures are Spotify's analysis of a track
+type AudioFeatures struct {
+	// Tempo is in beats per minute
+	Tempo float64 `json:"tempo"`
+	// Key is the pitch class, 0 for C up to 11 for B, or -1 if unknown
+	Key int `json:"key"`
+	// Mode is 1 for major and 0 for minor
+	Mode          int     `json:"mode"`
+	Energy        float64 `json:"energy"`
+	Danceability  float64 `json:"danceability"`
+	TimeSignature int     `json:"time_signature"`
+}
+
+// GetAudioFeatures gets the audio features of a track by its ID. Spotify
+// only serves them to apps that had access before November 2024; others
+// get an APIError matching ErrForbidden.
+func (p *PlayerService) GetAudioFeatures(trackID string) (*AudioFeatures, error) {
+	var features AudioFeatures
+	if _, err := p.getJSON("/audio-features/"+url.PathEscape(trackID), nil, &features); err != nil {
+		return nil, err
+	}
+	return &features, nil
+}
+
+// pitchClasses names the keys by pitch class
+var pitchClasses = []string{"C", "C♯", "D", "E♭", "E", "F", "F♯", "G", "A♭", "A", "B♭", "B"}
+
+// camelotMajor and camelotMinor are the Camelot wheel numbers of each
+// pitch class, used by DJs for harmonic mixing
+var (
+	camelotMajor = []int{8, 3, 10, 5, 12, 7, 2, 9, 4, 11, 6, 1}
+	camelotMinor = []int{5, 12, 7, 2, 9, 4, 11, 6, 1, 8, 3, 10}
+)
+
+// KeyName returns the key in musical notation, e.g. "A minor", or "" if
+// it is unknown
+func (f *AudioFeatures) KeyName() string {
+	if f.Key < 0 || f.Key >= len(pitchClasses) {
+		return ""
+	}
+	if f.Mode == 1 {
+		return pitchClasses[f.Key] + " major"
+	}
+	return pitchClasses[f.Key] + " minor"
+}
+
+// Camelot returns the key in Camelot notation, e.g. "8A" for A minor, or
+// "" if it is unknown
+func (f *AudioFeatures) Camelot() string {
+	if f.Key < 0 || f.Key >= len(pitchClasses) {
+		return ""
+	}
+	if f.Mode == 1 {
+		return fmt.Sprintf("%dB", camelotMajor[f.Key])
+	}
+	return fmt.Sprintf("%dA", camelotMinor[f.Key])
+}
@@ -0,0 +1,170 @@
+// player/format.go
+package player
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStatusFormat is the format used by FormatTrackInfo when none is configured
+const DefaultStatusFormat = "{state} {artist} - {track} ({progress}/{duration})"
+
+// Glyphs holds the symbols used for the {state} format token
+type Glyphs struct {
+	Playing string
+	Paused  string
+}
+
+// DefaultGlyphs returns the default Unicode playback-state glyphs
+func DefaultGlyphs() Glyphs {
+	return Glyphs{
+		Playing: "▶",
+		Paused:  "⏸",
+	}
+}
+
+// Format renders the playback state using a format string.
+//
+// Supported tokens:
+//
+//	{state}    playing/paused glyph
+//	{artist}    comma-separated artist names (show name for episodes)
+//	{track}     track or episode name
+//	{album}     album name
+//	{progress}  elapsed time (m:ss)
+//	{duration}  track length (m:ss)
+//	{remaining} time left (m:ss)
+//	{released}  album release date, as precise as Spotify knows it
+//	{popularity} popularity from 0 to 100 (empty for episodes)
+func (c *CurrentlyPlaying) Format(format string, glyphs Glyphs) string {
+	state := glyphs.Paused
+	if c.IsPlaying {
+		state = glyphs.Playing
+	}
+
+	// Format artists
+	artistNames := make([]string, len(c.Track.Artists))
+	for i, artist := range c.Track.Artists {
+		artistNames[i] = artist.Name
+	}
+	if len(artistNames) == 0 && c.Track.Show != nil {
+		artistNames = append(artistNames, c.Track.Show.Name)
+	}
+
+	replacer := strings.NewReplacer(
+		"{state}", state,
+		"{artist}", strings.Join(artistNames, ", "),
+		"{track}", c.Track.Name,
+		"{album}", c.Track.Album.Name,
+		"{progress}", FormatDuration(c.Progress),
+		"{duration}", formatKnownDuration(c.Track.Duration),
+		"{remaining}", c.remaining(),
+		"{released}", c.Track.Album.Released(),
+		"{popularity}", c.Track.popularity(),
+	)
+
+	return strings.TrimSpace(replacer.Replace(format))
+}
+
+// DisplayName formats an item as "Artist - Title", using the show name
+// for podcast episodes
+func (t *Track) DisplayName() string {
+	names := make([]string, 0, len(t.Artists))
+	for _, artist := range t.Artists {
+		names = append(names, artist.Name)
+	}
+	if len(names) == 0 && t.Show != nil {
+		names = append(names, t.Show.Name)
+	}
+
+	if len(names) == 0 {
+		return t.Name
+	}
+	return strings.Join(names, ", ") + " - " + t.Name
+}
+
+// Released formats the release date according to its precision, e.g.
+// "1981", "Dec 1981" or "15 Dec 1981". It is empty if the date is unknown.
+func (a *Album) Released() string {
+	layouts := map[string][2]string{
+		"year":  {"2006", "2006"},
+		"month": {"2006-01", "Jan 2006"},
+		"day":   {"2006-01-02", "2 Jan 2006"},
+	}
+
+	layout, ok := layouts[a.ReleaseDatePrecision]
+	if !ok {
+		return a.ReleaseDate
+	}
+	date, err := time.Parse(layout[0], a.ReleaseDate)
+	if err != nil {
+		return a.ReleaseDate
+	}
+	return date.Format(layout[1])
+}
+
+// popularity formats the popularity score, empty for episodes
+func (t *Track) popularity() string {
+	if t.Type == "episode" {
+		return ""
+	}
+	return strconv.Itoa(t.Popularity)
+}
+
+// unknownDuration is shown for durations Spotify does not report,
+// e.g. for local files and some ads
+const unknownDuration = "--:--"
+
+// remaining formats the time left, or unknownDuration when the length is unknown
+func (c *CurrentlyPlaying) remaining() string {
+	if c.Track.Duration <= 0 {
+		return unknownDuration
+	}
+	return FormatDuration(int(c.Remaining().Milliseconds()))
+}
+
+// formatKnownDuration formats a duration that is zero when unknown
+func formatKnownDuration(ms int) string {
+	if ms <= 0 {
+		return unknownDuration
+	}
+	return FormatDuration(ms)
+}
+
+// FormatDuration formats milliseconds as m:ss, negative durations as 0:00
+func FormatDuration(ms int) string {
+	if ms < 0 {
+		ms = 0
+	}
+	d := time.Duration(ms) * time.Millisecond
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// ParseTimestamp parses "m:ss" or "h:mm:ss" into milliseconds
+func ParseTimestamp(s string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q, use m:ss or h:mm:ss", s)
+	}
+
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil || v < 0 || part == "" {
+			return 0, fmt.Errorf("invalid timestamp %q, use m:ss or h:mm:ss", s)
+		}
+		// Everything after the leading field is limited to 0-59
+		if i > 0 && (v > 59 || len(part) != 2) {
+			return 0, fmt.Errorf("invalid timestamp %q, use m:ss or h:mm:ss", s)
+		}
+		values[i] = v
+	}
+
+	seconds := 0
+	for _, v := range values {
+		seconds = seconds*60 + v
+	}
+	return seconds * 1000, nil
+}
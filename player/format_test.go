@@ -0,0 +1,48 @@
+// player/format_test.go
+package player
+
+import "testing"
+
+func TestFormatUnknownDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress int
+		duration int
+		want     string
+	}{
+		{"known", 61000, 180000, "1:01 / 3:00 / 1:59"},
+		{"zero", 61000, 0, "1:01 / --:-- / --:--"},
+		{"negative", 61000, -1, "1:01 / --:-- / --:--"},
+		{"nothing played", 0, 0, "0:00 / --:-- / --:--"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CurrentlyPlaying{Progress: tt.progress, Track: Track{Duration: tt.duration}}
+			got := c.Format("{progress} / {duration} / {remaining}", DefaultGlyphs())
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		ms   int
+		want string
+	}{
+		{0, "0:00"},
+		{-5000, "0:00"},
+		{999, "0:00"},
+		{59000, "0:59"},
+		{61000, "1:01"},
+		{3725000, "62:05"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.ms); got != tt.want {
+			t.Errorf("FormatDuration(%d) = %q, want %q", tt.ms, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// player/history.go
+package player
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PlayHistory is an entry of the recently played list
+type PlayHistory struct {
+	Track    Track     `json:"track"`
+	PlayedAt time.Time `json:"played_at"`
+	Context  *Context  `json:"context"`
+}
+
+// RecentlyPlayed is one page of the recently played list, newest first
+type RecentlyPlayed struct {
+	Items []PlayHistory `json:"items"`
+	Next  string        `json:"next"`
+	Limit int           `json:"limit"`
+	// Cursors are Unix millisecond timestamps for paging
+	Cursors struct {
+		After  string `json:"after"`
+		Before string `json:"before"`
+	} `json:"cursors"`
+}
+
+// GetRecentlyPlayed gets up to limit recently played tracks (at most 50,
+// the default when zero), newest first. If after is not zero, only tracks
+// played after that Unix time in milliseconds are returned. Spotify only
+// lists tracks that played for at least 30 seconds, and no episodes.
+func (p *PlayerService) GetRecentlyPlayed(limit int, after int64) (*RecentlyPlayed, error) {
+	return p.getRecentlyPlayed(limit, "after", after)
+}
+
+// GetRecentlyPlayedBefore is like GetRecentlyPlayed but returns tracks
+// played before the Unix time in milliseconds before. Passing the Before
+// cursor of a page returns the page after it, i.e. older tracks.
+func (p *PlayerService) GetRecentlyPlayedBefore(limit int, before int64) (*RecentlyPlayed, error) {
+	return p.getRecentlyPlayed(limit, "before", before)
+}
+
+// Cursor returns the Before cursor as a Unix time in milliseconds, or 0
+// when there are no older tracks
+func (r *RecentlyPlayed) Cursor() int64 {
+	if r.Next == "" {
+		return 0
+	}
+	before, err := strconv.ParseInt(r.Cursors.Before, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return before
+}
+
+// getRecentlyPlayed fetches the recently played list with the cursor
+// parameter param ("after" or "before"), which is left out when zero
+func (p *PlayerService) getRecentlyPlayed(limit int, param string, cursor int64) (*RecentlyPlayed, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 50
+	}
+
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(limit))
+	if cursor > 0 {
+		query.Set(param, fmt.Sprint(cursor))
+	}
+
+	var history RecentlyPlayed
+	if _, err := p.getJSON("/me/player/recently-played", query, &history); err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
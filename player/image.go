@@ -0,0 +1,37 @@
+// player/image.go
+package player
+
+// Image is one size of a cover image. Width and Height are 0 when Spotify
+// does not know them.
+type Image struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// BestImageURL returns the URL of the widest cover image that is at most
+// maxWidth pixels wide, or of the narrowest one if none is. A maxWidth of 0
+// or less picks the widest. It is empty if the album has no images.
+func (a *Album) BestImageURL(maxWidth int) string {
+	var best, narrowest *Image
+	for i := range a.Images {
+		img := &a.Images[i]
+		if narrowest == nil || img.Width < narrowest.Width {
+			narrowest = img
+		}
+		if maxWidth > 0 && img.Width > maxWidth {
+			continue
+		}
+		if best == nil || img.Width > best.Width {
+			best = img
+		}
+	}
+
+	if best == nil {
+		best = narrowest
+	}
+	if best == nil {
+		return ""
+	}
+	return best.URL
+}
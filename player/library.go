@@ -0,0 +1,79 @@
+// player/library.go
+package player
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ErrNothingToSave is returned by ToggleSaveCurrentTrack when no track is playing
+var ErrNothingToSave = errors.New("no track is playing")
+
+// SaveTrack adds a track to the user's Liked Songs
+func (p *PlayerService) SaveTrack(uri string) error {
+	id, err := trackID(uri)
+	if err != nil {
+		return err
+	}
+	return p.send("PUT", "/me/tracks", nil, url.Values{"ids": {id}})
+}
+
+// RemoveTrack removes a track from the user's Liked Songs
+func (p *PlayerService) RemoveTrack(uri string) error {
+	id, err := trackID(uri)
+	if err != nil {
+		return err
+	}
+	return p.send("DELETE", "/me/tracks", nil, url.Values{"ids": {id}})
+}
+
+// IsTrackSaved reports whether a track is in the user's Liked Songs
+func (p *PlayerService) IsTrackSaved(uri string) (bool, error) {
+	id, err := trackID(uri)
+	if err != nil {
+		return false, err
+	}
+
+	var saved []bool
+	if _, err := p.getJSON("/me/tracks/contains", url.Values{"ids": {id}}, &saved); err != nil {
+		return false, err
+	}
+
+	return len(saved) > 0 && saved[0], nil
+}
+
+// ToggleSaveCurrentTrack saves the playing track if it is not in the user's
+// Liked Songs and removes it otherwise. It returns whether the track is
+// saved now. Errors from a token without the library scopes match
+// ErrInsufficientScope.
+func (p *PlayerService) ToggleSaveCurrentTrack() (bool, error) {
+	current, err := p.GetCurrentlyPlaying()
+	if err != nil {
+		return false, err
+	}
+	if current.Track.URI == "" {
+		return false, ErrNothingToSave
+	}
+
+	saved, err := p.IsTrackSaved(current.Track.URI)
+	if err != nil {
+		return false, err
+	}
+
+	if saved {
+		return false, p.RemoveTrack(current.Track.URI)
+	}
+	return true, p.SaveTrack(current.Track.URI)
+}
+
+// trackID returns the ID of a track URI
+func trackID(uri string) (string, error) {
+	kind, id, err := ParseURI(uri)
+	if err != nil {
+		return "", err
+	}
+	if kind != "track" {
+		return "", errors.New("only tracks can be saved to Liked Songs")
+	}
+	return id, nil
+}
@@ -0,0 +1,30 @@
+// player/library_test.go
+package player
+
+import "testing"
+
+func TestTrackID(t *testing.T) {
+	tests := []struct {
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{"spotify:track:6rqhFgbbKwnb9MLmUQDhG6", "6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"spotify:episode:512ojhOuo1ktJprKbVcKyQ", "", true},
+		{"spotify:album:1DFixLWuPkv3KT3TnV35m3", "", true},
+		{"spotify:local:Artist:Album:Song:200", "", true},
+		{"not a uri", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		id, err := trackID(tt.uri)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("trackID(%q) error = %v, want error %v", tt.uri, err, tt.wantErr)
+			continue
+		}
+		if id != tt.want {
+			t.Errorf("trackID(%q) = %q, want %q", tt.uri, id, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// player/play.go
+package player
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrItemUnavailable is returned when a track or episode no longer exists
+// or cannot be looked up
+var ErrItemUnavailable = errors.New("item is no longer available")
+
+// playRequest is the body of a start/resume playback request
+type playRequest struct {
+	ContextURI string      `json:"context_uri,omitempty"`
+	URIs       []string    `json:"uris,omitempty"`
+	Offset     *playOffset `json:"offset,omitempty"`
+	PositionMs int         `json:"position_ms,omitempty"`
+}
+
+// playOffset selects where in a context playback starts
+type playOffset struct {
+	Position *int   `json:"position,omitempty"`
+	URI      string `json:"uri,omitempty"`
+}
+
+// startPlayback starts playback of a specific context or list of items
+func (p *PlayerService) startPlayback(body playRequest) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return p.send("PUT", "/me/player/play", bytes.NewReader(data), nil)
+}
+
+// PlayURI plays a playlist, album, artist or show context. If trackURI is
+// set, playback starts at that item of the context. Without a context,
+// trackURI is played on its own.
+func (p *PlayerService) PlayURI(contextURI, trackURI string) error {
+	if contextURI == "" {
+		if _, _, err := ParseURI(trackURI); err != nil {
+			return err
+		}
+		return p.startPlayback(playRequest{URIs: []string{trackURI}})
+	}
+
+	if _, _, err := ParseURI(contextURI); err != nil {
+		return err
+	}
+	body := playRequest{ContextURI: contextURI}
+	if trackURI != "" {
+		body.Offset = &playOffset{URI: trackURI}
+	}
+	return p.startPlayback(body)
+}
+
+// PlayContext plays an album, playlist, artist or show, starting at the
+// 0-based offset within it
+func (p *PlayerService) PlayContext(contextURI string, offset int) error {
+	if _, _, err := ParseURI(contextURI); err != nil {
+		return err
+	}
+	if offset < 0 {
+		return fmt.Errorf("offset must not be negative, got %d", offset)
+	}
+
+	body := playRequest{ContextURI: contextURI}
+	if offset > 0 {
+		body.Offset = &playOffset{Position: &offset}
+	}
+	return p.startPlayback(body)
+}
+
+// PlayTracks plays a list of tracks or episodes in order
+func (p *PlayerService) PlayTracks(uris []string) error {
+	if len(uris) == 0 {
+		return errors.New("no tracks given")
+	}
+	for _, uri := range uris {
+		if _, _, err := ParseURI(uri); err != nil {
+			return err
+		}
+	}
+
+	return p.startPlayback(playRequest{URIs: uris})
+}
+
+// PlayURIAt starts playing a single track or episode at positionMs.
+// The position is validated against the item's duration when it is known,
+// and ErrItemUnavailable is returned if the item no longer exists.
+func (p *PlayerService) PlayURIAt(uri string, positionMs int) error {
+	kind, _, err := ParseURI(uri)
+	if err != nil {
+		return err
+	}
+	if kind != "track" && kind != "episode" {
+		return fmt.Errorf("cannot start a %s at a position, only tracks and episodes", kind)
+	}
+	if positionMs < 0 {
+		return fmt.Errorf("position must not be negative, got %dms", positionMs)
+	}
+
+	if positionMs > 0 {
+		duration, err := p.itemDuration(uri)
+		if err != nil {
+			return err
+		}
+		if err := checkEnd(positionMs, duration); err != nil {
+			return err
+		}
+	}
+
+	return p.startPlayback(playRequest{
+		URIs:       []string{uri},
+		PositionMs: positionMs,
+	})
+}
+
+// PlayAlbumTrack plays an album starting at the given 1-based track number,
+// so the rest of the album keeps playing afterwards
+func (p *PlayerService) PlayAlbumTrack(albumURI string, trackNumber int) error {
+	kind, _, err := ParseURI(albumURI)
+	if err != nil {
+		return err
+	}
+	if kind != "album" {
+		return fmt.Errorf("%s is not an album URI", albumURI)
+	}
+	if trackNumber < 1 {
+		return fmt.Errorf("track number must be at least 1, got %d", trackNumber)
+	}
+
+	position := trackNumber - 1
+	err = p.startPlayback(playRequest{
+		ContextURI: albumURI,
+		Offset:     &playOffset{Position: &position},
+	})
+	if err != nil {
+		// Spotify rejects offsets past the last track with a generic error
+		return fmt.Errorf("playing track %d of %s: %w", trackNumber, albumURI, err)
+	}
+
+	return nil
+}
+
+// itemDuration looks up the duration of a track or episode in milliseconds
+func (p *PlayerService) itemDuration(uri string) (int, error) {
+	kind, id, err := ParseURI(uri)
+	if err != nil {
+		return 0, err
+	}
+
+	var item Track
+	if _, err := p.getJSON(fmt.Sprintf("/%ss/%s", kind, id), nil, &item); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusBadRequest) {
+			return 0, fmt.Errorf("%w: %s", ErrItemUnavailable, uri)
+		}
+		return 0, err
+	}
+
+	return item.Duration, nil
+}
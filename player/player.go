@@ -3,11 +3,14 @@ package player
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-//	"net/url"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -17,13 +20,24 @@ const (
 	baseURL = "https://api.spotify.com/v1"
 )
 
-// Track represents a Spotify track
+// Track represents a Spotify track or podcast episode
 type Track struct {
 	Name     string   `json:"name"`
 	Artists  []Artist `json:"artists"`
 	Album    Album    `json:"album"`
 	Duration int      `json:"duration_ms"`
 	URI      string   `json:"uri"`
+	Type     string   `json:"type"`
+	Show     *Show    `json:"show,omitempty"`
+	// Popularity is Spotify's 0-100 popularity score, not set for episodes
+	Popularity int `json:"popularity"`
+}
+
+// Show represents the podcast an episode belongs to
+type Show struct {
+	Name      string `json:"name"`
+	Publisher string `json:"publisher"`
+	URI       string `json:"uri"`
 }
 
 // Artist represents a Spotify artist
@@ -36,14 +50,94 @@ type Artist struct {
 type Album struct {
 	Name string `json:"name"`
 	URI  string `json:"uri"`
+	// ReleaseDate is as precise as ReleaseDatePrecision says ("year",
+	// "month" or "day"), e.g. "1981", "1981-12" or "1981-12-15"
+	ReleaseDate          string `json:"release_date"`
+	ReleaseDatePrecision string `json:"release_date_precision"`
+	// Images are the cover art in several sizes, widest first
+	Images []Image `json:"images"`
 }
 
 // CurrentlyPlaying represents the currently playing track
 type CurrentlyPlaying struct {
-	IsPlaying bool    `json:"is_playing"`
-	Track     Track   `json:"item"`
-	Progress  int     `json:"progress_ms"`
-	Timestamp int64   `json:"timestamp"`
+	IsPlaying bool     `json:"is_playing"`
+	Track     Track    `json:"item"`
+	Progress  int      `json:"progress_ms"`
+	Timestamp int64    `json:"timestamp"`
+	Type      string   `json:"currently_playing_type"`
+	Context   *Context `json:"context"`
+	Actions   Actions  `json:"actions"`
+	// Device is the active device. Only the full playback state from
+	// GetPlaybackState and Subscribe includes it, it is nil otherwise.
+	Device *Device `json:"device"`
+
+	// FetchedAt is the local time the state was received
+	FetchedAt time.Time `json:"-"`
+	// Raw is the full response, for fields the struct does not decode or
+	// could not decode
+	Raw json.RawMessage `json:"-"`
+}
+
+// Context represents the playlist, album, artist or show being played from
+type Context struct {
+	Type string `json:"type"`
+	URI  string `json:"uri"`
+}
+
+// Actions holds the controls Spotify currently forbids, keyed by action
+// name (e.g. "skipping_next", "seeking")
+type Actions struct {
+	Disallows map[string]bool `json:"disallows"`
+}
+
+// allowed reports whether an action is not disallowed
+func (c *CurrentlyPlaying) allowed(action string) bool {
+	return !c.Actions.Disallows[action]
+}
+
+// CanSkipNext reports whether skipping to the next item is allowed
+func (c *CurrentlyPlaying) CanSkipNext() bool { return c.allowed("skipping_next") }
+
+// CanSkipPrevious reports whether going back to the previous item is allowed
+func (c *CurrentlyPlaying) CanSkipPrevious() bool { return c.allowed("skipping_prev") }
+
+// CanSeek reports whether seeking within the current item is allowed
+func (c *CurrentlyPlaying) CanSeek() bool { return c.allowed("seeking") }
+
+// CanPause reports whether pausing is allowed
+func (c *CurrentlyPlaying) CanPause() bool { return c.allowed("pausing") }
+
+// CanResume reports whether resuming is allowed
+func (c *CurrentlyPlaying) CanResume() bool { return c.allowed("resuming") }
+
+// IsEpisode reports whether the current item is a podcast episode
+func (c *CurrentlyPlaying) IsEpisode() bool {
+	return c.Type == "episode" || c.Track.Type == "episode"
+}
+
+// ProgressFraction returns how much of the current item has played, from
+// 0 to 1. It is 0 when the duration is unknown.
+func (c *CurrentlyPlaying) ProgressFraction() float64 {
+	if c.Track.Duration <= 0 {
+		return 0
+	}
+	fraction := float64(c.Progress) / float64(c.Track.Duration)
+	if fraction < 0 {
+		return 0
+	}
+	if fraction > 1 {
+		return 1
+	}
+	return fraction
+}
+
+// Remaining returns the time left of the current item, never negative.
+// It is 0 when the duration is unknown.
+func (c *CurrentlyPlaying) Remaining() time.Duration {
+	if c.Track.Duration <= 0 || c.Progress >= c.Track.Duration {
+		return 0
+	}
+	return time.Duration(c.Track.Duration-c.Progress) * time.Millisecond
 }
 
 // TokenProvider is an interface for getting OAuth tokens
@@ -54,22 +148,108 @@ type TokenProvider interface {
 
 // PlayerService handles Spotify playback control
 type PlayerService struct {
-	token         *oauth2.Token
 	tokenProvider TokenProvider
-	client        *http.Client
+	format        string
+	glyphs        Glyphs
+
+	// clientMu guards the cached client and the token it was built from
+	clientMu sync.Mutex
+	token    *oauth2.Token
+	client   *http.Client
+
+	// httpClient, when set, is used instead of the token provider's client
+	// and apiURL is the base URL requests go to, both replaceable for tests
+	httpClient *http.Client
+	apiURL     string
+
+	// rateLimitWait bounds the time a request waits for rate limits
+	rateLimitWait time.Duration
+
+	pollInterval time.Duration
+	subs         subscriptions
+
+	warnMu      sync.Mutex
+	warnHandler func(error)
+}
+
+// Option configures a PlayerService
+type Option func(*PlayerService)
+
+// WithStatusFormat sets the format string used by FormatTrackInfo
+func WithStatusFormat(format string) Option {
+	return func(p *PlayerService) {
+		if format != "" {
+			p.format = format
+		}
+	}
+}
+
+// WithGlyphs sets the playing/paused glyphs used by the {state} token
+func WithGlyphs(glyphs Glyphs) Option {
+	return func(p *PlayerService) {
+		if glyphs.Playing != "" {
+			p.glyphs.Playing = glyphs.Playing
+		}
+		if glyphs.Paused != "" {
+			p.glyphs.Paused = glyphs.Paused
+		}
+	}
+}
+
+// WithHTTPClient makes the service send every request with client instead
+// of the token provider's, e.g. one with a stub http.RoundTripper in tests.
+// The client must add the Authorization header itself if one is needed.
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *PlayerService) {
+		p.httpClient = client
+	}
+}
+
+// WithBaseURL sends requests to url instead of the Spotify Web API
+func WithBaseURL(url string) Option {
+	return func(p *PlayerService) {
+		if url != "" {
+			p.apiURL = strings.TrimSuffix(url, "/")
+		}
+	}
 }
 
 // NewPlayerService creates a new player service
-func NewPlayerService(token *oauth2.Token, tokenProvider TokenProvider) *PlayerService {
-	return &PlayerService{
+func NewPlayerService(token *oauth2.Token, tokenProvider TokenProvider, opts ...Option) *PlayerService {
+	p := &PlayerService{
 		token:         token,
 		tokenProvider: tokenProvider,
+		format:        DefaultStatusFormat,
+		glyphs:        DefaultGlyphs(),
+		pollInterval:  defaultPollInterval,
+		apiURL:        baseURL,
+		rateLimitWait: DefaultRateLimitWait,
 	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
-// getClient gets a valid HTTP client
+// getClient gets a valid HTTP client.
+// The client is cached for as long as the token provider keeps returning the
+// same access token, and rebuilt as soon as the token has been refreshed.
 func (p *PlayerService) getClient() (*http.Client, error) {
-	if p.client != nil {
+	if p.httpClient != nil {
+		return p.httpClient, nil
+	}
+	
+	token, err := p.tokenProvider.GetToken()
+	if err != nil {
+		return nil, err
+	}
+	
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+	
+	if p.client != nil && p.token != nil && p.token.AccessToken == token.AccessToken {
 		return p.client, nil
 	}
 	
@@ -78,158 +258,160 @@ func (p *PlayerService) getClient() (*http.Client, error) {
 		return nil, err
 	}
 	
+	p.token = token
 	p.client = client
 	return client, nil
 }
 
 // GetCurrentlyPlaying gets the currently playing track
 func (p *PlayerService) GetCurrentlyPlaying() (*CurrentlyPlaying, error) {
-	client, err := p.getClient()
+	raw, err := p.GetCurrentlyPlayingRaw()
 	if err != nil {
 		return nil, err
 	}
 	
-	// Make the request
-	resp, err := client.Get(baseURL + "/me/player/currently-playing")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	
 	// Check if no content (no track playing)
-	if resp.StatusCode == http.StatusNoContent {
-		return &CurrentlyPlaying{IsPlaying: false}, nil
-	}
-	
-	// Check for errors
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: %s, %s", resp.Status, string(body))
+	if raw == nil {
+		return &CurrentlyPlaying{IsPlaying: false, FetchedAt: time.Now()}, nil
 	}
 	
 	// Parse the response
 	var current CurrentlyPlaying
-	if err := json.NewDecoder(resp.Body).Decode(&current); err != nil {
+	if err := p.decode(raw, &current); err != nil {
 		return nil, err
 	}
+	current.FetchedAt = time.Now()
+	current.Raw = raw
 	
 	return &current, nil
 }
 
-// Play starts or resumes playback
-func (p *PlayerService) Play() error {
-	client, err := p.getClient()
-	if err != nil {
-		return err
-	}
+// GetCurrentlyPlayingRaw returns the undecoded currently-playing response,
+// for fields the typed structs do not expose. It returns nil when nothing is
+// playing.
+func (p *PlayerService) GetCurrentlyPlayingRaw() (json.RawMessage, error) {
+	// Episodes are only returned when explicitly requested
+	query := url.Values{"additional_types": {"episode"}}
 	
-	// Create request
-	req, err := http.NewRequest("PUT", baseURL+"/me/player/play", nil)
+	resp, err := p.request("GET", "/me/player/currently-playing", nil, query)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer resp.Body.Close()
 	
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+	// Check if no content (no track playing)
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
 	}
-	defer resp.Body.Close()
 	
-	// Check for errors
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: %s, %s", resp.Status, string(body))
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
 	
-	return nil
+	return json.RawMessage(body), nil
+}
+
+// Play starts or resumes playback
+func (p *PlayerService) Play() error {
+	return p.send("PUT", "/me/player/play", nil, nil)
 }
 
 // Pause pauses playback
 func (p *PlayerService) Pause() error {
-	client, err := p.getClient()
-	if err != nil {
-		return err
-	}
-	
-	// Create request
-	req, err := http.NewRequest("PUT", baseURL+"/me/player/pause", nil)
-	if err != nil {
-		return err
-	}
-	
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	
-	// Check for errors
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: %s, %s", resp.Status, string(body))
-	}
-	
-	return nil
+	return p.send("PUT", "/me/player/pause", nil, nil)
 }
 
 // Next skips to the next track
 func (p *PlayerService) Next() error {
-	client, err := p.getClient()
-	if err != nil {
-		return err
+	return p.send("POST", "/me/player/next", nil, nil)
+}
+
+// Previous goes to the previous track
+func (p *PlayerService) Previous() error {
+	return p.send("POST", "/me/player/previous", nil, nil)
+}
+
+// ErrNothingPlaying is returned by operations on the current item when
+// nothing is playing
+var ErrNothingPlaying = errors.New("nothing is playing")
+
+// Seek moves the playback position of the current item. Negative positions
+// and positions at or beyond the item's end are rejected.
+func (p *PlayerService) Seek(positionMs int) error {
+	if positionMs < 0 {
+		return fmt.Errorf("position must not be negative, got %dms", positionMs)
 	}
 	
-	// Create request
-	req, err := http.NewRequest("POST", baseURL+"/me/player/next", nil)
+	current, err := p.GetCurrentlyPlaying()
 	if err != nil {
 		return err
 	}
-	
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
+	if current.Track.URI == "" {
+		return ErrNothingPlaying
+	}
+	if err := checkEnd(positionMs, current.Track.Duration); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 	
-	// Check for errors
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: %s, %s", resp.Status, string(body))
+	return p.seek(positionMs)
+}
+
+// checkEnd rejects positions at or beyond the end of an item, where there is
+// nothing left to play. A duration of 0 is unknown and allows any position.
+func checkEnd(positionMs, durationMs int) error {
+	if durationMs > 0 && positionMs >= durationMs {
+		return fmt.Errorf("position %s is beyond the end of the item (%s)",
+			FormatDuration(positionMs), FormatDuration(durationMs))
 	}
-	
 	return nil
 }
 
-// Previous goes to the previous track
-func (p *PlayerService) Previous() error {
-	client, err := p.getClient()
+// SeekRelative moves the playback position by deltaMs, which may be
+// negative, clamping at the start and end of the current item
+func (p *PlayerService) SeekRelative(deltaMs int) error {
+	current, err := p.GetCurrentlyPlaying()
 	if err != nil {
 		return err
 	}
-	
-	// Create request
-	req, err := http.NewRequest("POST", baseURL+"/me/player/previous", nil)
-	if err != nil {
-		return err
+	if current.Track.URI == "" {
+		return ErrNothingPlaying
 	}
 	
-	// Make the request
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+	position := current.Progress + deltaMs
+	if position < 0 {
+		position = 0
+	}
+	if current.Track.Duration > 0 && position > current.Track.Duration {
+		position = current.Track.Duration
 	}
-	defer resp.Body.Close()
 	
-	// Check for errors
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: %s, %s", resp.Status, string(body))
+	return p.seek(position)
+}
+
+// seek sends a seek request without validating the position
+func (p *PlayerService) seek(positionMs int) error {
+	query := url.Values{"position_ms": {strconv.Itoa(positionMs)}}
+	return p.send("PUT", "/me/player/seek", nil, query)
+}
+
+// ErrPlaybackSpeedUnsupported is returned by SetPlaybackSpeed when the
+// playback speed cannot be changed remotely
+var ErrPlaybackSpeedUnsupported = errors.New("changing playback speed is not supported on this device")
+
+// SetPlaybackSpeed asks the active device to play at the given speed
+// (0.5 to 3.5, as offered by Spotify's apps).
+//
+// The Web API does not expose playback speed for any device, so after
+// validating the speed this always returns ErrPlaybackSpeedUnsupported. It
+// exists so callers can offer the control and report this consistently
+// should Spotify add an endpoint.
+func (p *PlayerService) SetPlaybackSpeed(speed float64) error {
+	if speed < 0.5 || speed > 3.5 {
+		return fmt.Errorf("playback speed must be between 0.5 and 3.5, got %.2f", speed)
 	}
 	
-	return nil
+	return ErrPlaybackSpeedUnsupported
 }
 
 // PlayPause toggles play/pause
@@ -254,23 +436,14 @@ func (p *PlayerService) FormatTrackInfo() (string, error) {
 		return "", err
 	}
 	
-	if !current.IsPlaying || current.Track.Name == "" {
-		return "No track currently playing", nil
-	}
-	
-	// Format artists
-	artistNames := make([]string, len(current.Track.Artists))
-	for i, artist := range current.Track.Artists {
-		artistNames[i] = artist.Name
+	return p.Format(current), nil
+}
+
+// Format formats an already fetched playback state with the configured format
+func (p *PlayerService) Format(current *CurrentlyPlaying) string {
+	if current == nil || current.Track.Name == "" {
+		return "No track currently playing"
 	}
-	artists := strings.Join(artistNames, ", ")
 	
-	// Format progress
-	progress := time.Duration(current.Progress) * time.Millisecond
-	duration := time.Duration(current.Track.Duration) * time.Millisecond
-	progressStr := fmt.Sprintf("%d:%02d/%d:%02d", 
-		int(progress.Minutes()), int(progress.Seconds())%60,
-		int(duration.Minutes()), int(duration.Seconds())%60)
-	
-	return fmt.Sprintf("%s - %s (%s)", artists, current.Track.Name, progressStr), nil
-}
\ No newline at end of file
+	return current.Format(p.format, p.glyphs)
+}
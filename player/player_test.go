@@ -0,0 +1,332 @@
+// player/player_test.go
+package player
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// reply is one scripted API response
+type reply struct {
+	status     int
+	retryAfter string
+	body       string
+}
+
+// scripted answers requests with replies in order, repeating the last
+// one, and records the requests it saw
+type scripted struct {
+	mu       sync.Mutex
+	replies  []reply
+	requests []*http.Request
+}
+
+func (s *scripted) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	i := len(s.requests)
+	if i >= len(s.replies) {
+		i = len(s.replies) - 1
+	}
+	s.requests = append(s.requests, r)
+	rep := s.replies[i]
+	s.mu.Unlock()
+
+	if rep.retryAfter != "" {
+		w.Header().Set("Retry-After", rep.retryAfter)
+	}
+	if rep.body != "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(rep.status)
+	w.Write([]byte(rep.body))
+}
+
+func (s *scripted) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+const playingJSON = `{
+	"is_playing": true,
+	"progress_ms": 61000,
+	"currently_playing_type": "track",
+	"item": {
+		"name": "Song",
+		"uri": "spotify:track:abc",
+		"type": "track",
+		"duration_ms": 180000,
+		"artists": [{"name": "Artist"}],
+		"album": {"name": "Album"}
+	}
+}`
+
+func TestGetCurrentlyPlaying(t *testing.T) {
+	tests := []struct {
+		name      string
+		replies   []reply
+		wantCalls int
+		wantTrack string
+		wantErr   error
+	}{
+		{"playing", []reply{{status: 200, body: playingJSON}}, 1, "Song", nil},
+		{"nothing playing", []reply{{status: 204}}, 1, "", nil},
+		{"unauthorized then ok", []reply{{status: 401}, {status: 200, body: playingJSON}}, 2, "Song", nil},
+		{"unauthorized twice", []reply{{status: 401}}, 2, "", ErrUnauthorized},
+		{"rate limited then ok", []reply{{status: 429, retryAfter: "2"}, {status: 200, body: playingJSON}}, 2, "Song", nil},
+		{"rate limited", []reply{{status: 429, retryAfter: "1"}}, maxRateLimitRetries + 1, "", ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recordSleeps(t)
+			api := &scripted{replies: tt.replies}
+			p := newTestPlayer(t, api)
+
+			current, err := p.GetCurrentlyPlaying()
+			if api.calls() != tt.wantCalls {
+				t.Errorf("made %d requests, want %d", api.calls(), tt.wantCalls)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetCurrentlyPlaying() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCurrentlyPlaying() error = %v", err)
+			}
+
+			if current.Track.Name != tt.wantTrack {
+				t.Errorf("track = %q, want %q", current.Track.Name, tt.wantTrack)
+			}
+			if current.IsPlaying != (tt.wantTrack != "") {
+				t.Errorf("IsPlaying = %v", current.IsPlaying)
+			}
+			if current.FetchedAt.IsZero() {
+				t.Error("FetchedAt not set")
+			}
+		})
+	}
+}
+
+func TestGetCurrentlyPlayingRequest(t *testing.T) {
+	api := &scripted{replies: []reply{{status: 200, body: playingJSON}}}
+	p := newTestPlayer(t, api)
+
+	current, err := p.GetCurrentlyPlaying()
+	if err != nil {
+		t.Fatalf("GetCurrentlyPlaying() error = %v", err)
+	}
+
+	r := api.requests[0]
+	if r.Method != "GET" || r.URL.Path != "/me/player/currently-playing" {
+		t.Errorf("request = %s %s", r.Method, r.URL.Path)
+	}
+	if got := r.URL.Query().Get("additional_types"); got != "episode" {
+		t.Errorf("additional_types = %q, want episode", got)
+	}
+	if current.Progress != 61000 || current.Track.Duration != 180000 {
+		t.Errorf("progress %d of %d", current.Progress, current.Track.Duration)
+	}
+	if len(current.Track.Artists) != 1 || current.Track.Artists[0].Name != "Artist" {
+		t.Errorf("artists = %+v", current.Track.Artists)
+	}
+	if len(current.Raw) == 0 {
+		t.Error("Raw not set")
+	}
+}
+
+func TestPlayPause(t *testing.T) {
+	controls := []struct {
+		name string
+		call func(*PlayerService) error
+		path string
+	}{
+		{"Play", (*PlayerService).Play, "/me/player/play"},
+		{"Pause", (*PlayerService).Pause, "/me/player/pause"},
+	}
+	tests := []struct {
+		name      string
+		replies   []reply
+		wantCalls int
+		wantWaits []time.Duration
+		wantErr   error
+	}{
+		{"ok", []reply{{status: 200}}, 1, nil, nil},
+		{"no content", []reply{{status: 204}}, 1, nil, nil},
+		{"unauthorized then ok", []reply{{status: 401}, {status: 204}}, 2, nil, nil},
+		{"unauthorized twice", []reply{{status: 401}}, 2, nil, ErrUnauthorized},
+		{"rate limited then ok", []reply{{status: 429, retryAfter: "2"}, {status: 204}}, 2, []time.Duration{2 * time.Second}, nil},
+		{"rate limited", []reply{{status: 429, retryAfter: "1"}}, maxRateLimitRetries + 1,
+			[]time.Duration{time.Second, time.Second, time.Second}, ErrRateLimited},
+		{"no device", []reply{{status: 404, body: `{"error": {"status": 404, "message": "Player command failed: No active device found", "reason": "NO_ACTIVE_DEVICE"}}`}},
+			1, nil, ErrNoActiveDevice},
+	}
+
+	for _, c := range controls {
+		for _, tt := range tests {
+			t.Run(c.name+"/"+tt.name, func(t *testing.T) {
+				waits := recordSleeps(t)
+				api := &scripted{replies: tt.replies}
+				p := newTestPlayer(t, api)
+
+				err := c.call(p)
+				if tt.wantErr == nil && err != nil {
+					t.Fatalf("%s() error = %v", c.name, err)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("%s() error = %v, want %v", c.name, err, tt.wantErr)
+				}
+				if api.calls() != tt.wantCalls {
+					t.Errorf("made %d requests, want %d", api.calls(), tt.wantCalls)
+				}
+				if !equalDurations(*waits, tt.wantWaits) {
+					t.Errorf("waited %v, want %v", *waits, tt.wantWaits)
+				}
+				for _, r := range api.requests {
+					if r.Method != "PUT" || r.URL.Path != c.path {
+						t.Errorf("request = %s %s, want PUT %s", r.Method, r.URL.Path, c.path)
+					}
+				}
+			})
+		}
+	}
+}
+
+// swappableTokens is a TokenProvider whose token can be replaced, as a
+// re-login does. Like an oauth2 client built from a fixed token, each
+// client it returns keeps the token current when it was built.
+type swappableTokens struct {
+	mu      sync.Mutex
+	token   string
+	clients int
+}
+
+func (s *swappableTokens) set(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+func (s *swappableTokens) GetToken() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &oauth2.Token{AccessToken: s.token}, nil
+}
+
+func (s *swappableTokens) GetClient() (*http.Client, error) {
+	token, _ := s.GetToken()
+	s.mu.Lock()
+	s.clients++
+	s.mu.Unlock()
+	return oauth2.NewClient(nil, oauth2.StaticTokenSource(token)), nil
+}
+
+func TestRequestsUseTokenAfterReauth(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	tokens := &swappableTokens{token: "old"}
+	p := NewPlayerService(nil, tokens, WithBaseURL(srv.URL))
+
+	for _, step := range []func() error{
+		p.Play,
+		p.Pause,
+		func() error { tokens.set("new"); return p.Play() },
+		p.Pause,
+	} {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"Bearer old", "Bearer old", "Bearer new", "Bearer new"}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Errorf("request %d sent %q, want %q", i+1, seen[i], want[i])
+		}
+	}
+	if tokens.clients != 2 {
+		t.Errorf("built %d clients, want one per token", tokens.clients)
+	}
+}
+
+func TestRefreshClientRebuildsClient(t *testing.T) {
+	tokens := &swappableTokens{token: "token"}
+	api := &scripted{replies: []reply{{status: 401}, {status: 204}}}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+	p := NewPlayerService(nil, tokens, WithBaseURL(srv.URL))
+
+	if err := p.Play(); err != nil {
+		t.Fatal(err)
+	}
+	if tokens.clients != 2 {
+		t.Errorf("built %d clients, want a new one after the 401", tokens.clients)
+	}
+}
+
+func TestSeekAndPlayAtBounds(t *testing.T) {
+	const trackJSON = `{"name": "Song", "uri": "spotify:track:abc", "type": "track", "duration_ms": 180000}`
+	calls := []struct {
+		name string
+		// body answers the request for the item's duration
+		body string
+		call func(p *PlayerService, positionMs int) error
+		path string
+	}{
+		{"Seek", playingJSON, (*PlayerService).Seek, "/me/player/seek"},
+		{"PlayURIAt", trackJSON, func(p *PlayerService, positionMs int) error {
+			return p.PlayURIAt("spotify:track:abc", positionMs)
+		}, "/me/player/play"},
+	}
+	tests := []struct {
+		name     string
+		position int
+		wantErr  bool
+	}{
+		{"start", 0, false},
+		{"just before the end", 179999, false},
+		{"at the end", 180000, true},
+		{"beyond the end", 200000, true},
+		{"negative", -1, true},
+	}
+
+	for _, c := range calls {
+		for _, tt := range tests {
+			t.Run(c.name+"/"+tt.name, func(t *testing.T) {
+				api := &scripted{replies: []reply{{status: 200, body: c.body}, {status: 204}}}
+				p := newTestPlayer(t, api)
+
+				err := c.call(p, tt.position)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("%s(%d) error = %v, want error %v", c.name, tt.position, err, tt.wantErr)
+				}
+
+				sent := false
+				for _, r := range api.requests {
+					sent = sent || (r.Method == "PUT" && r.URL.Path == c.path)
+				}
+				if sent == tt.wantErr {
+					t.Errorf("%s(%d) sent PUT %s = %v", c.name, tt.position, c.path, sent)
+				}
+			})
+		}
+	}
+}
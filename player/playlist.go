@@ -0,0 +1,129 @@
+// player/playlist.go
+package player
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// PlaylistItem is an entry of a playlist. Track is nil for items Spotify
+// no longer returns, e.g. removed or region-locked tracks.
+type PlaylistItem struct {
+	AddedAt string `json:"added_at"`
+	IsLocal bool   `json:"is_local"`
+	Track   *Track `json:"track"`
+}
+
+// TrackPage is one page of a playlist's items
+type TrackPage struct {
+	Items  []PlaylistItem `json:"items"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+	Next   string         `json:"next"`
+}
+
+// GetPlaylistTracks gets one page of a playlist's items. limit defaults to
+// 50 (the maximum) when zero.
+func (p *PlayerService) GetPlaylistTracks(playlistID string, limit, offset int) (*TrackPage, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 50
+	}
+
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(limit))
+	query.Set("offset", fmt.Sprint(offset))
+	query.Set("additional_types", "track,episode")
+
+	var page TrackPage
+	if _, err := p.getJSON("/playlists/"+url.PathEscape(playlistID)+"/tracks", query, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// Playlist is a playlist the user owns or follows
+type Playlist struct {
+	ID    string        `json:"id"`
+	Name  string        `json:"name"`
+	URI   string        `json:"uri"`
+	Owner PlaylistOwner `json:"owner"`
+	// Tracks only carries the number of items, fetch them with
+	// GetPlaylistTracks
+	Tracks struct {
+		Total int `json:"total"`
+	} `json:"tracks"`
+}
+
+// TrackCount returns the number of items in the playlist
+func (pl Playlist) TrackCount() int {
+	return pl.Tracks.Total
+}
+
+// PlaylistOwner is the user a playlist belongs to
+type PlaylistOwner struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+}
+
+// Name returns the owner's display name, or the user ID without one
+func (o PlaylistOwner) Name() string {
+	if o.DisplayName != "" {
+		return o.DisplayName
+	}
+	return o.ID
+}
+
+// PlaylistPage is one page of the user's playlists
+type PlaylistPage struct {
+	Items  []Playlist `json:"items"`
+	Total  int        `json:"total"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+	Next   string     `json:"next"`
+}
+
+// GetUserPlaylists gets one page of the playlists the user owns or
+// follows. limit defaults to 50 (the maximum) when zero. The next page
+// starts at offset+len(Items), and there is none when Next is empty.
+func (p *PlayerService) GetUserPlaylists(limit, offset int) (*PlaylistPage, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 50
+	}
+
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(limit))
+	query.Set("offset", fmt.Sprint(offset))
+
+	var page PlaylistPage
+	if _, err := p.getJSON("/me/playlists", query, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// GetAllPlaylistTracks follows the pagination of GetPlaylistTracks and
+// returns every item that still resolves to a track or episode
+func (p *PlayerService) GetAllPlaylistTracks(playlistID string) ([]Track, error) {
+	var tracks []Track
+	offset := 0
+	for {
+		page, err := p.GetPlaylistTracks(playlistID, 0, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			if item.Track != nil {
+				tracks = append(tracks, *item.Track)
+			}
+		}
+
+		offset += len(page.Items)
+		if page.Next == "" || len(page.Items) == 0 {
+			return tracks, nil
+		}
+	}
+}
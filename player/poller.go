@@ -0,0 +1,131 @@
+// player/poller.go
+package player
+
+import (
+	"fmt"
+	"time"
+)
+
+// Poll strategies accepted by NewPoller
+const (
+	PollFixed    = "fixed"
+	PollAdaptive = "adaptive"
+	PollBackoff  = "backoff"
+)
+
+// Poller decides how long to wait before fetching the playback state again
+type Poller interface {
+	// Next returns the delay after a poll that returned current, which is
+	// nil if the poll failed
+	Next(current *CurrentlyPlaying) time.Duration
+}
+
+// NewPoller returns the poller for a strategy name, polling every interval
+// in the normal case. backoffAfter and backoffMax set After and Max of the
+// backoff strategy. An empty name selects the fixed strategy.
+func NewPoller(strategy string, interval time.Duration, backoffAfter int, backoffMax time.Duration) (Poller, error) {
+	switch strategy {
+	case "", PollFixed:
+		return FixedPoller{Interval: interval}, nil
+	case PollAdaptive:
+		return AdaptivePoller{Interval: interval}, nil
+	case PollBackoff:
+		return &BackoffPoller{Interval: interval, After: backoffAfter, Max: backoffMax}, nil
+	}
+	return nil, fmt.Errorf("unknown poll strategy %q, use %q, %q or %q", strategy, PollFixed, PollAdaptive, PollBackoff)
+}
+
+// PollerFunc adapts a function to the Poller interface
+type PollerFunc func(current *CurrentlyPlaying) time.Duration
+
+// Next implements Poller
+func (f PollerFunc) Next(current *CurrentlyPlaying) time.Duration {
+	return f(current)
+}
+
+// FixedPoller polls at a constant interval, one request per interval
+// whatever the player is doing
+type FixedPoller struct {
+	Interval time.Duration
+}
+
+// Next implements Poller
+func (f FixedPoller) Next(current *CurrentlyPlaying) time.Duration {
+	return f.Interval
+}
+
+// Adaptive poller delays
+const (
+	// adaptivePaused is the delay while playback is paused
+	adaptivePaused = 5 * time.Second
+	// adaptiveIdle is the delay while nothing is playing at all
+	adaptiveIdle = 15 * time.Second
+	// adaptiveBoundary is how soon after the expected end of an item the
+	// next poll happens, and the shortest delay used
+	adaptiveBoundary = 250 * time.Millisecond
+)
+
+// AdaptivePoller polls every Interval while playing, but right after the
+// expected end of the playing item so track changes show up promptly, and
+// much less often while paused or idle. It makes fewer requests than
+// FixedPoller overall, at the cost of noticing playback started elsewhere
+// up to 15 seconds late.
+type AdaptivePoller struct {
+	Interval time.Duration
+}
+
+// Next implements Poller
+func (a AdaptivePoller) Next(current *CurrentlyPlaying) time.Duration {
+	switch {
+	case current == nil:
+		return a.Interval
+	case current.Track.URI == "":
+		return adaptiveIdle
+	case !current.IsPlaying:
+		return adaptivePaused
+	}
+
+	// Poll just after the item should have ended
+	if current.Track.Duration > 0 {
+		if remaining := current.Remaining(); remaining < a.Interval {
+			return remaining + adaptiveBoundary
+		}
+	}
+
+	return a.Interval
+}
+
+// BackoffPoller polls every Interval while playing. Once After polls in a
+// row found nothing playing, it doubles the delay with every further poll
+// up to Max, and goes back to Interval as soon as playback resumes.
+// It keeps state, so it must only be used by one goroutine.
+type BackoffPoller struct {
+	Interval time.Duration
+	After    int
+	Max      time.Duration
+
+	// idle counts the polls in a row that found nothing playing
+	idle int
+}
+
+// Next implements Poller
+func (b *BackoffPoller) Next(current *CurrentlyPlaying) time.Duration {
+	// A failed poll says nothing about playback
+	if current == nil {
+		return b.Interval
+	}
+	if current.IsPlaying {
+		b.idle = 0
+		return b.Interval
+	}
+
+	b.idle++
+	delay := b.Interval
+	for i := b.After; i < b.idle && delay < b.Max; i++ {
+		delay *= 2
+	}
+	if delay > b.Max && b.Max >= b.Interval {
+		delay = b.Max
+	}
+	return delay
+}
@@ -0,0 +1,126 @@
+// player/queue.go
+package player
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// queueSpacing is the pause between consecutive queue requests in
+// QueueTracks, to stay clear of Spotify's rate limits
+const queueSpacing = 250 * time.Millisecond
+
+// ErrNoContext is returned when an operation needs a playlist, album or show
+// context but the current playback has none
+var ErrNoContext = errors.New("nothing is playing from a playlist, album or show")
+
+// Queue represents the user's playback queue
+type Queue struct {
+	CurrentlyPlaying Track   `json:"currently_playing"`
+	QueueItems       []Track `json:"queue"`
+}
+
+// GetQueue gets the currently playing item and the upcoming queue
+func (p *PlayerService) GetQueue() (*Queue, error) {
+	var queue Queue
+	if _, err := p.getJSON("/me/player/queue", nil, &queue); err != nil {
+		return nil, err
+	}
+
+	return &queue, nil
+}
+
+// AddToQueue adds a track or episode to the end of the queue. Without an
+// active device the error matches ErrNoActiveDevice.
+func (p *PlayerService) AddToQueue(uri string) error {
+	kind, _, err := ParseURI(uri)
+	if err != nil {
+		return err
+	}
+	if kind != "track" && kind != "episode" {
+		return fmt.Errorf("only tracks and episodes can be queued, %s is a %s", uri, kind)
+	}
+
+	return p.send("POST", "/me/player/queue", nil, url.Values{"uri": {uri}})
+}
+
+// QueueTracks adds several items to the queue in order, one request at a
+// time. progress, if not nil, is called after each item with the number of
+// items processed so far and that item's error. Failures do not stop the
+// remaining items; all of them are returned joined together.
+func (p *PlayerService) QueueTracks(uris []string, progress func(done int, uri string, err error)) error {
+	var errs []error
+	for i, uri := range uris {
+		if i > 0 {
+			time.Sleep(queueSpacing)
+		}
+
+		err := p.AddToQueue(uri)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", uri, err))
+		}
+		if progress != nil {
+			progress(i+1, uri, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ClearQueue makes a best effort to drop upcoming queued items.
+//
+// Spotify has no endpoint for clearing the queue, and the queue endpoint does
+// not say which items were added by the user and which come from the context.
+// ClearQueue therefore restarts the current context at the current item and
+// position, which discards the queue Spotify built for that context. Whether
+// items queued by hand survive this depends on the Spotify client; use
+// SkipQueued to skip a known number of items instead. ErrNoContext is returned
+// when playback is not from a playlist, album or show.
+func (p *PlayerService) ClearQueue() error {
+	current, err := p.GetCurrentlyPlaying()
+	if err != nil {
+		return err
+	}
+
+	if current.Context == nil || current.Context.URI == "" || current.Track.URI == "" {
+		return ErrNoContext
+	}
+
+	return p.startPlayback(playRequest{
+		ContextURI: current.Context.URI,
+		Offset:     &playOffset{URI: current.Track.URI},
+		PositionMs: current.Progress,
+	})
+}
+
+// SkipQueued skips past the next n items in the queue, so the item after
+// them plays. That takes n+1 skips, as the first one leaves the current item.
+// n is capped at the number of queued items. It returns how many queued
+// items were skipped, fewer than n when a skip fails.
+func (p *PlayerService) SkipQueued(n int) (int, error) {
+	queue, err := p.GetQueue()
+	if err != nil {
+		return 0, err
+	}
+
+	if n > len(queue.QueueItems) {
+		n = len(queue.QueueItems)
+	}
+	if n <= 0 {
+		return 0, nil
+	}
+
+	for i := 0; i <= n; i++ {
+		if err := p.Next(); err != nil {
+			// The first skip only left the current item
+			if i > 0 {
+				i--
+			}
+			return i, err
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,77 @@
+// player/queue_test.go
+package player
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const queueJSON = `{
+	"currently_playing": {"name": "Now", "uri": "spotify:track:now"},
+	"queue": [
+		{"name": "One", "uri": "spotify:track:one"},
+		{"name": "Two", "uri": "spotify:track:two"},
+		{"name": "Three", "uri": "spotify:track:three"}
+	]
+}`
+
+// queueAPI serves queueJSON and counts skips, failing the skip numbered failAt
+type queueAPI struct {
+	skips  int
+	failAt int
+}
+
+func (q *queueAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/me/player/queue":
+		w.Write([]byte(queueJSON))
+	case r.Method == "POST" && r.URL.Path == "/me/player/next":
+		q.skips++
+		if q.skips == q.failAt {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestSkipQueued(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int
+		failAt    int
+		wantSkips int
+		wantN     int
+		wantErr   error
+	}{
+		{"none", 0, 0, 0, 0, nil},
+		{"one", 1, 0, 2, 1, nil},
+		{"two", 2, 0, 3, 2, nil},
+		{"capped at the queue", 10, 0, 4, 3, nil},
+		{"negative", -1, 0, 0, 0, nil},
+		{"current item fails", 3, 1, 1, 0, ErrForbidden},
+		{"first queued item fails", 3, 2, 2, 0, ErrForbidden},
+		{"later failure", 3, 3, 3, 1, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &queueAPI{failAt: tt.failAt}
+			p := newTestPlayer(t, api)
+
+			n, err := p.SkipQueued(tt.n)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SkipQueued(%d) error = %v, want %v", tt.n, err, tt.wantErr)
+			}
+			if n != tt.wantN {
+				t.Errorf("SkipQueued(%d) = %d, want %d", tt.n, n, tt.wantN)
+			}
+			if api.skips != tt.wantSkips {
+				t.Errorf("sent %d skips, want %d", api.skips, tt.wantSkips)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// player/request.go
+package player
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxRateLimitRetries is how often a rate-limited request is retried
+	maxRateLimitRetries = 3
+	// DefaultRateLimitWait is how long a request waits for rate limits in
+	// total, over all its retries, unless WithRateLimitWait says otherwise
+	DefaultRateLimitWait = 30 * time.Second
+)
+
+// sleep waits between retries, replaced in tests
+var sleep = time.Sleep
+
+// WithRateLimitWait sets how long a request may wait in total for a rate
+// limit to pass before the 429 is returned as an error. One-shot commands
+// use a short wait so they never hang a tmux status line or key binding.
+// Zero returns rate limit errors at once.
+func WithRateLimitWait(wait time.Duration) Option {
+	return func(p *PlayerService) {
+		if wait >= 0 {
+			p.rateLimitWait = wait
+		}
+	}
+}
+
+// request sends an API request to path (relative to the API base URL) and
+// returns the response if Spotify answered with a success status. Any other
+// status is returned as an *APIError, and transport failures as a
+// *NetworkError. A body is sent as JSON.
+//
+// On 401 Unauthorized the cached client is dropped and the request retried
+// once, as the token may have been refreshed or replaced in the meantime.
+// On 429 Too Many Requests it waits as long as the Retry-After header says
+// and retries, up to maxRateLimitRetries times and as long as the waits add
+// up to no more than the service's rate limit wait.
+// The caller must close the response body.
+func (p *PlayerService) request(method, path string, body io.Reader, query url.Values) (*http.Response, error) {
+	// Buffer the body so the request can be sent again
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoint := p.apiURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	rateLimited := 0
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		resp, err := p.do(method, endpoint, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			resp.Body.Close()
+			p.RefreshClient()
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && rateLimited < maxRateLimitRetries {
+			if wait := retryAfter(resp); waited+wait <= p.rateLimitWait {
+				resp.Body.Close()
+				rateLimited++
+				waited += wait
+				sleep(wait)
+				continue
+			}
+		}
+
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+}
+
+// do sends a single request with the current client
+func (p *PlayerService) do(method, endpoint string, payload []byte) (*http.Response, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	// Create request
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Make the request
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, wrapNetworkError(err)
+	}
+	return resp, nil
+}
+
+// retryAfter returns how long a 429 response asks to wait, in whole
+// seconds as Spotify sends it
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 1 {
+		return time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// send makes a request whose response body is not needed
+func (p *PlayerService) send(method, path string, body io.Reader, query url.Values) error {
+	resp, err := p.request(method, path, body, query)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// getJSON makes a GET request and decodes the response into v.
+// It returns false, leaving v untouched, when Spotify answered 204 No Content.
+func (p *PlayerService) getJSON(path string, query url.Values, v interface{}) (bool, error) {
+	resp, err := p.request("GET", path, nil, query)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return false, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+
+	return true, p.decode(data, v)
+}
+
+// RefreshClient drops the cached client so the next request builds a new
+// one from the token provider, e.g. after logging in again
+func (p *PlayerService) RefreshClient() {
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+	p.client = nil
+}
@@ -0,0 +1,143 @@
+// player/request_test.go
+package player
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestPlayer returns a player service that sends its requests to handler
+func newTestPlayer(t *testing.T, handler http.Handler, opts ...Option) *PlayerService {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	opts = append([]Option{WithHTTPClient(srv.Client()), WithBaseURL(srv.URL)}, opts...)
+	return NewPlayerService(nil, nil, opts...)
+}
+
+// recordSleeps replaces sleep for the test and returns the waits it was
+// asked for
+func recordSleeps(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() { sleep = time.Sleep })
+	return &waits
+}
+
+// rateLimited answers the first n requests with 429 and retryAfter, and
+// the rest with 204
+func rateLimited(n int32, retryAfter string, calls *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= n {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestRateLimitWaitsAndRetries(t *testing.T) {
+	waits := recordSleeps(t)
+	var calls int32
+	p := newTestPlayer(t, rateLimited(2, "3", &calls))
+
+	if err := p.Play(); err != nil {
+		t.Fatalf("Play() = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("made %d requests, want 3", calls)
+	}
+	if want := []time.Duration{3 * time.Second, 3 * time.Second}; !equalDurations(*waits, want) {
+		t.Errorf("waited %v, want %v", *waits, want)
+	}
+}
+
+func TestRateLimitGivesUpAfterRetries(t *testing.T) {
+	waits := recordSleeps(t)
+	var calls int32
+	p := newTestPlayer(t, rateLimited(100, "1", &calls))
+
+	err := p.Play()
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Play() = %v, want ErrRateLimited", err)
+	}
+	if calls != maxRateLimitRetries+1 {
+		t.Errorf("made %d requests, want %d", calls, maxRateLimitRetries+1)
+	}
+	if len(*waits) != maxRateLimitRetries {
+		t.Errorf("waited %d times, want %d", len(*waits), maxRateLimitRetries)
+	}
+}
+
+func TestRateLimitWaitBounded(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		retryAfter string
+		wantCalls  int32
+		wantWaits  int
+	}{
+		{"no wait", []Option{WithRateLimitWait(0)}, "1", 1, 0},
+		{"retry after too long", []Option{WithRateLimitWait(2 * time.Second)}, "5", 1, 0},
+		{"budget used up", []Option{WithRateLimitWait(2 * time.Second)}, "1", 3, 2},
+		{"default rejects an hour", nil, "3600", 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waits := recordSleeps(t)
+			var calls int32
+			p := newTestPlayer(t, rateLimited(100, tt.retryAfter, &calls), tt.opts...)
+
+			err := p.Pause()
+			if !errors.Is(err, ErrRateLimited) {
+				t.Fatalf("Pause() = %v, want ErrRateLimited", err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("made %d requests, want %d", calls, tt.wantCalls)
+			}
+			if len(*waits) != tt.wantWaits {
+				t.Errorf("waited %v, want %d waits", *waits, tt.wantWaits)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", time.Second},
+		{"0", time.Second},
+		{"abc", time.Second},
+		{"1", time.Second},
+		{"7", 7 * time.Second},
+	}
+
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{"Retry-After": {tt.header}}}
+		if got := retryAfter(resp); got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func equalDurations(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
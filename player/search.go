@@ -0,0 +1,75 @@
+// player/search.go
+package player
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// searchTypes are the item types Search supports
+var searchTypes = map[string]bool{
+	"track":  true,
+	"album":  true,
+	"artist": true,
+}
+
+// SearchResults holds the items found by Search, by type
+type SearchResults struct {
+	Tracks  []Track
+	Albums  []Album
+	Artists []Artist
+}
+
+// Search looks up tracks, albums and/or artists matching query. types
+// defaults to all three. limit is per type, 20 when zero and at most 50.
+func (p *PlayerService) Search(query string, types []string, limit int) (*SearchResults, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("search query is empty")
+	}
+
+	if len(types) == 0 {
+		types = []string{"track", "album", "artist"}
+	}
+	for _, t := range types {
+		if !searchTypes[t] {
+			return nil, fmt.Errorf("cannot search for %q, use track, album or artist", t)
+		}
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	// url.Values escapes spaces and special characters in the query
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("type", strings.Join(types, ","))
+	params.Set("limit", fmt.Sprint(limit))
+
+	var response struct {
+		Tracks struct {
+			Items []Track `json:"items"`
+		} `json:"tracks"`
+		Albums struct {
+			Items []Album `json:"items"`
+		} `json:"albums"`
+		Artists struct {
+			Items []Artist `json:"items"`
+		} `json:"artists"`
+	}
+	if _, err := p.getJSON("/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	return &SearchResults{
+		Tracks:  response.Tracks.Items,
+		Albums:  response.Albums.Items,
+		Artists: response.Artists.Items,
+	}, nil
+}
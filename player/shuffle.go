@@ -0,0 +1,71 @@
+// player/shuffle.go
+package player
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Repeat modes accepted by SetRepeat
+const (
+	RepeatOff     = "off"
+	RepeatTrack   = "track"
+	RepeatContext = "context"
+)
+
+// PlaybackState is the full player state, which adds the shuffle and repeat
+// settings to what GetCurrentlyPlaying returns, and sets its Device
+type PlaybackState struct {
+	CurrentlyPlaying
+	ShuffleState bool   `json:"shuffle_state"`
+	RepeatState  string `json:"repeat_state"`
+}
+
+// GetPlaybackState gets the full player state. Like GetCurrentlyPlaying it
+// returns an empty, paused state when nothing is playing.
+func (p *PlayerService) GetPlaybackState() (*PlaybackState, error) {
+	query := url.Values{"additional_types": {"episode"}}
+
+	var state PlaybackState
+	ok, err := p.getJSON("/me/player", query, &state)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		state = PlaybackState{RepeatState: RepeatOff}
+	}
+	state.FetchedAt = time.Now()
+
+	return &state, nil
+}
+
+// SetShuffle turns shuffle on or off
+func (p *PlayerService) SetShuffle(state bool) error {
+	query := url.Values{"state": {strconv.FormatBool(state)}}
+	return p.send("PUT", "/me/player/shuffle", nil, query)
+}
+
+// SetRepeat sets the repeat mode to RepeatOff, RepeatTrack or RepeatContext
+func (p *PlayerService) SetRepeat(mode string) error {
+	switch mode {
+	case RepeatOff, RepeatTrack, RepeatContext:
+	default:
+		return fmt.Errorf("unknown repeat mode %q, use %q, %q or %q", mode, RepeatOff, RepeatTrack, RepeatContext)
+	}
+
+	return p.send("PUT", "/me/player/repeat", nil, url.Values{"state": {mode}})
+}
+
+// NextRepeatMode returns the mode after mode in the order the Spotify apps
+// cycle through them: off, context, track
+func NextRepeatMode(mode string) string {
+	switch mode {
+	case RepeatOff:
+		return RepeatContext
+	case RepeatContext:
+		return RepeatTrack
+	}
+	return RepeatOff
+}
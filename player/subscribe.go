@@ -0,0 +1,228 @@
+// player/subscribe.go
+package player
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// defaultPollInterval is how often the shared poller fetches the playback
+// state when no Poller is set
+const defaultPollInterval = 1 * time.Second
+
+// WithPollInterval sets how often Subscribe's shared poller fetches the
+// playback state
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *PlayerService) {
+		if interval > 0 {
+			p.pollInterval = interval
+		}
+	}
+}
+
+// subscriptions is the state of the shared poller behind Subscribe
+type subscriptions struct {
+	mu   sync.Mutex
+	subs map[chan CurrentlyPlaying]struct{}
+	// stop ends the poller; nil while no one is subscribed
+	stop chan struct{}
+	// refresh asks the poller to poll now; nil while no one is subscribed
+	refresh chan struct{}
+	// last is the most recently published state
+	last *CurrentlyPlaying
+	// poller decides the delay between polls, nil for the poll interval
+	poller Poller
+	// onError is called with the error of each failed poll
+	onError func(error)
+}
+
+// SetPoller makes Subscribe's shared poller wait as long as poller says
+// between polls instead of the fixed poll interval. Failed polls are passed
+// to it as nil.
+func (p *PlayerService) SetPoller(poller Poller) {
+	p.subs.mu.Lock()
+	defer p.subs.mu.Unlock()
+	p.subs.poller = poller
+}
+
+// SetPollErrorHandler sets a function called with the error of each failed
+// poll of Subscribe's shared poller
+func (p *PlayerService) SetPollErrorHandler(handler func(error)) {
+	p.subs.mu.Lock()
+	defer p.subs.mu.Unlock()
+	p.subs.onError = handler
+}
+
+// Refresh makes Subscribe's shared poller poll right away, e.g. after a
+// control changed the playback state. It does nothing while no one is
+// subscribed.
+func (p *PlayerService) Refresh() {
+	s := &p.subs
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.refresh == nil {
+		return
+	}
+	select {
+	case s.refresh <- struct{}{}:
+	default:
+		// A refresh is already pending
+	}
+}
+
+// Subscribe returns a channel receiving the playback state whenever it
+// changes, and a function that cancels the subscription and closes the
+// channel.
+//
+// All subscribers share one poller, started by the first subscription and
+// stopped when the last one is cancelled, so adding consumers adds no API
+// traffic. New subscribers immediately receive the latest known state.
+// Polls that fail are reported to the poll error handler, and the next
+// successful one is published even when unchanged. A subscriber that falls behind only sees the
+// most recent state.
+func (p *PlayerService) Subscribe() (<-chan CurrentlyPlaying, func()) {
+	ch := make(chan CurrentlyPlaying, 1)
+
+	s := &p.subs
+	s.mu.Lock()
+	if s.subs == nil {
+		s.subs = make(map[chan CurrentlyPlaying]struct{})
+	}
+	s.subs[ch] = struct{}{}
+	if s.last != nil {
+		ch <- *s.last
+	}
+	if s.stop == nil {
+		s.stop = make(chan struct{})
+		s.refresh = make(chan struct{}, 1)
+		go p.poll(s.stop, s.refresh)
+	}
+	s.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			delete(s.subs, ch)
+			close(ch)
+			if len(s.subs) == 0 {
+				close(s.stop)
+				s.stop = nil
+				s.refresh = nil
+				s.last = nil
+			}
+		})
+	}
+
+	return ch, cancel
+}
+
+// poll fetches the playback state until stop is closed and publishes it
+// whenever it differs from the previous one. The full playback state costs
+// the same one request as the currently playing item and adds the device.
+func (p *PlayerService) poll(stop <-chan struct{}, refresh <-chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var last *CurrentlyPlaying
+	for {
+		select {
+		case <-timer.C:
+		case <-refresh:
+		case <-stop:
+			return
+		}
+
+		var current *CurrentlyPlaying
+		state, err := p.GetPlaybackState()
+		if err != nil {
+			// Publish the next success even if unchanged, so subscribers
+			// see the failure end
+			last = nil
+			p.pollFailed(stop, err)
+		} else {
+			current = &state.CurrentlyPlaying
+			if last == nil || !sameState(last, current) {
+				last = current
+				p.publish(stop, current)
+			}
+		}
+
+		// Since Go 1.23 Reset drops a pending tick, so no drain is needed
+		timer.Reset(p.nextPoll(current))
+	}
+}
+
+// nextPoll returns the delay before the next poll after current, which is
+// nil when the poll failed
+func (p *PlayerService) nextPoll(current *CurrentlyPlaying) time.Duration {
+	p.subs.mu.Lock()
+	poller := p.subs.poller
+	p.subs.mu.Unlock()
+
+	if poller == nil {
+		return p.pollInterval
+	}
+	return poller.Next(current)
+}
+
+// pollFailed passes the error of a failed poll to the poll error handler,
+// unless the poller was stopped while the request was in flight
+func (p *PlayerService) pollFailed(stop <-chan struct{}, err error) {
+	s := &p.subs
+	s.mu.Lock()
+	handler := s.onError
+	s.mu.Unlock()
+
+	select {
+	case <-stop:
+		return
+	default:
+	}
+	if handler != nil {
+		handler(err)
+	}
+}
+
+// publish sends a state to every subscriber without blocking, replacing a
+// state the subscriber has not received yet
+func (p *PlayerService) publish(stop <-chan struct{}, current *CurrentlyPlaying) {
+	s := &p.subs
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The poller may have been stopped while the request was in flight
+	select {
+	case <-stop:
+		return
+	default:
+	}
+
+	s.last = current
+	for ch := range s.subs {
+		select {
+		case ch <- *current:
+		default:
+			// Only publish sends, and it holds the lock, so after draining
+			// the buffered state there is room again
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- *current
+		}
+	}
+}
+
+// sameState reports whether two playback states are equal apart from when
+// they were fetched and the raw response
+func sameState(a, b *CurrentlyPlaying) bool {
+	x, y := *a, *b
+	x.FetchedAt, y.FetchedAt = time.Time{}, time.Time{}
+	x.Timestamp, y.Timestamp = 0, 0
+	x.Raw, y.Raw = nil, nil
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,94 @@
+// player/subscribe_test.go
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+// receive returns the next state from a subscription, failing the test if
+// none arrives soon
+func receive(t *testing.T, states <-chan CurrentlyPlaying) CurrentlyPlaying {
+	t.Helper()
+	select {
+	case current := <-states:
+		return current
+	case <-time.After(5 * time.Second):
+		t.Fatal("no state published")
+		return CurrentlyPlaying{}
+	}
+}
+
+func TestSubscribeRefresh(t *testing.T) {
+	api := &scripted{replies: []reply{{status: 200, body: playingJSON}, {status: 204}}}
+	p := newTestPlayer(t, api, WithPollInterval(time.Hour))
+
+	states, cancel := p.Subscribe()
+	defer cancel()
+
+	if current := receive(t, states); current.Track.Name != "Song" {
+		t.Errorf("first state plays %q, want Song", current.Track.Name)
+	}
+
+	// Without the refresh the next poll would be an hour away
+	p.Refresh()
+	if current := receive(t, states); current.Track.URI != "" {
+		t.Errorf("state after refresh plays %q, want nothing", current.Track.URI)
+	}
+
+	for _, r := range api.requests {
+		if r.URL.Path != "/me/player" {
+			t.Errorf("polled %s, want the full playback state", r.URL.Path)
+		}
+	}
+}
+
+func TestSubscribePollerAndErrors(t *testing.T) {
+	api := &scripted{replies: []reply{{status: 500}, {status: 200, body: playingJSON}}}
+	p := newTestPlayer(t, api)
+
+	polled := make(chan *CurrentlyPlaying, 2)
+	p.SetPoller(PollerFunc(func(current *CurrentlyPlaying) time.Duration {
+		polled <- current
+		return time.Hour
+	}))
+	failed := make(chan error, 1)
+	p.SetPollErrorHandler(func(err error) { failed <- err })
+
+	states, cancel := p.Subscribe()
+	defer cancel()
+
+	select {
+	case err := <-failed:
+		if err == nil {
+			t.Error("error handler called with nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("error handler not called for the failed poll")
+	}
+	if current := <-polled; current != nil {
+		t.Errorf("poller got %+v for the failed poll, want nil", current)
+	}
+
+	// The poller asked for an hour, so only the refresh polls again
+	p.Refresh()
+	if current := receive(t, states); current.Track.Name != "Song" {
+		t.Errorf("state after refresh plays %q, want Song", current.Track.Name)
+	}
+	if current := <-polled; current == nil || current.Track.Name != "Song" {
+		t.Errorf("poller got %+v, want the polled state", current)
+	}
+	if api.calls() != 2 {
+		t.Errorf("made %d requests, want 2", api.calls())
+	}
+}
+
+func TestRefreshWithoutSubscribers(t *testing.T) {
+	api := &scripted{replies: []reply{{status: 204}}}
+	p := newTestPlayer(t, api)
+
+	p.Refresh()
+	if api.calls() != 0 {
+		t.Errorf("made %d requests without subscribers", api.calls())
+	}
+}
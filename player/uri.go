@@ -0,0 +1,24 @@
+// player/uri.go
+package player
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseURI splits a Spotify URI such as "spotify:track:ID" into its kind
+// ("track", "album", "playlist", ...) and ID
+func ParseURI(uri string) (kind, id string, err error) {
+	parts := strings.Split(uri, ":")
+	if len(parts) < 3 || parts[0] != "spotify" {
+		return "", "", fmt.Errorf("invalid Spotify URI %q", uri)
+	}
+
+	// Legacy playlist URIs look like spotify:user:NAME:playlist:ID
+	kind, id = parts[len(parts)-2], parts[len(parts)-1]
+	if kind == "" || id == "" {
+		return "", "", fmt.Errorf("invalid Spotify URI %q", uri)
+	}
+
+	return kind, id, nil
+}
@@ -0,0 +1,37 @@
+// player/uri_test.go
+package player
+
+import "testing"
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		uri      string
+		wantKind string
+		wantID   string
+		wantErr  bool
+	}{
+		{"spotify:track:6rqhFgbbKwnb9MLmUQDhG6", "track", "6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"spotify:album:1DFixLWuPkv3KT3TnV35m3", "album", "1DFixLWuPkv3KT3TnV35m3", false},
+		{"spotify:playlist:37i9dQZF1DXcBWIGoYBM5M", "playlist", "37i9dQZF1DXcBWIGoYBM5M", false},
+		{"spotify:episode:512ojhOuo1ktJprKbVcKyQ", "episode", "512ojhOuo1ktJprKbVcKyQ", false},
+		{"spotify:user:someone:playlist:abc", "playlist", "abc", false},
+		{"", "", "", true},
+		{"spotify", "", "", true},
+		{"spotify:track", "", "", true},
+		{"spotify:track:", "", "", true},
+		{"spotify::abc", "", "", true},
+		{"https://open.spotify.com/track/abc", "", "", true},
+		{"apple:track:abc", "", "", true},
+	}
+
+	for _, tt := range tests {
+		kind, id, err := ParseURI(tt.uri)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseURI(%q) error = %v, want error %v", tt.uri, err, tt.wantErr)
+			continue
+		}
+		if kind != tt.wantKind || id != tt.wantID {
+			t.Errorf("ParseURI(%q) = %q, %q, want %q, %q", tt.uri, kind, id, tt.wantKind, tt.wantID)
+		}
+	}
+}
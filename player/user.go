@@ -0,0 +1,28 @@
+// player/user.go
+package player
+
+// UserProfile represents the current Spotify user
+type UserProfile struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	Country     string `json:"country"`
+	// Product is the subscription level ("premium", "free", ...). It is only
+	// returned when the token has the user-read-private scope.
+	Product string `json:"product"`
+}
+
+// IsFree reports whether the user is known to be on Spotify Free, which
+// cannot control playback through the Web API
+func (u *UserProfile) IsFree() bool {
+	return u.Product == "free" || u.Product == "open"
+}
+
+// GetUserProfile gets the profile of the current user
+func (p *PlayerService) GetUserProfile() (*UserProfile, error) {
+	var profile UserProfile
+	if _, err := p.getJSON("/me", nil, &profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
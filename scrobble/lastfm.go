@@ -0,0 +1,96 @@
+// scrobble/lastfm.go
+package scrobble
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lastFMURL is the Last.fm API endpoint
+const lastFMURL = "https://ws.audioscrobbler.com/2.0/"
+
+// LastFM scrobbles to Last.fm. It needs an API account's key and secret
+// and a session key authorising it for the user's Last.fm account.
+type LastFM struct {
+	apiKey     string
+	apiSecret  string
+	sessionKey string
+	client     *http.Client
+}
+
+// NewLastFM creates a Last.fm scrobbler
+func NewLastFM(apiKey, apiSecret, sessionKey string) *LastFM {
+	return &LastFM{
+		apiKey:     apiKey,
+		apiSecret:  apiSecret,
+		sessionKey: sessionKey,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Scrobble implements Scrobbler
+func (l *LastFM) Scrobble(track Track) error {
+	params := url.Values{
+		"method":    {"track.scrobble"},
+		"artist":    {track.Artist},
+		"track":     {track.Title},
+		"timestamp": {strconv.FormatInt(track.StartedAt.Unix(), 10)},
+		"api_key":   {l.apiKey},
+		"sk":        {l.sessionKey},
+	}
+	if track.Album != "" {
+		params.Set("album", track.Album)
+	}
+	if track.Duration > 0 {
+		params.Set("duration", strconv.Itoa(int(track.Duration.Seconds())))
+	}
+	params.Set("api_sig", l.sign(params))
+	params.Set("format", "json")
+
+	resp, err := l.client.PostForm(lastFMURL, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Errors look like {"error": 9, "message": "Invalid session key"}
+	var result struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil && result.Error != 0 {
+		return fmt.Errorf("last.fm error %d: %s", result.Error, result.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("last.fm error: %s", resp.Status)
+	}
+	return nil
+}
+
+// sign computes the api_sig of a request: the MD5 of all parameters
+// concatenated as name and value in name order, followed by the secret
+func (l *LastFM) sign(params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteString(params.Get(name))
+	}
+	b.WriteString(l.apiSecret)
+
+	sum := md5.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
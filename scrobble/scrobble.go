@@ -0,0 +1,161 @@
+// scrobble/scrobble.go
+package scrobble
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// Track is a listen to submit
+type Track struct {
+	Artist   string
+	Title    string
+	Album    string
+	Duration time.Duration
+	// StartedAt is when playback of the track started
+	StartedAt time.Time
+}
+
+// Scrobbler submits listens to a scrobbling service
+type Scrobbler interface {
+	Scrobble(track Track) error
+}
+
+// Last.fm's rules for what counts as a listen
+const (
+	// minDuration is the shortest track that is scrobbled
+	minDuration = 30 * time.Second
+	// maxThreshold is how long a track must have played at most, even if
+	// that is less than half of it
+	maxThreshold = 4 * time.Minute
+)
+
+// Tracker watches the playback state and scrobbles each track once it has
+// been listened to for half its duration or four minutes, whichever comes
+// first. Listening time is the time playback ran between polls, so seeking
+// ahead does not count. Episodes and tracks shorter than 30 seconds are not
+// scrobbled.
+//
+// Feed it every poll with Observe and every change of the playing item with
+// TrackChanged.
+type Tracker struct {
+	scrobbler Scrobbler
+
+	mu sync.Mutex
+	// watching is set once the first poll or track change was seen
+	watching bool
+	// last is the latest poll of the watched track, nil before the first
+	last *player.CurrentlyPlaying
+	// started is when the watched track started playing
+	started time.Time
+	// listened is how long the watched track has played so far
+	listened time.Duration
+	// done is set once the watched track has been scrobbled
+	done bool
+}
+
+// NewTracker creates a tracker submitting to scrobbler
+func NewTracker(scrobbler Scrobbler) *Tracker {
+	return &Tracker{scrobbler: scrobbler}
+}
+
+// Observe feeds a poll of the playback state to the tracker. When the
+// watched track crosses the threshold it is scrobbled in the background;
+// failures are dropped so they never disturb the player.
+func (t *Tracker) Observe(current *player.CurrentlyPlaying) {
+	if current == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// There is no track change for what was already playing at start
+	if !t.watching {
+		t.watch(current)
+	}
+	if t.last != nil && t.last.Track.URI == current.Track.URI {
+		t.count(current.FetchedAt)
+	}
+	t.last = current
+	t.check()
+}
+
+// TrackChanged starts watching the new item of a track change, after
+// counting the time the previous one played until the change. It has the
+// signature of a track change handler.
+func (t *Tracker) TrackChanged(change player.TrackChange) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.last != nil && change.Current != nil && !change.Current.FetchedAt.IsZero() {
+		switchedAt := change.Current.FetchedAt.Add(-time.Duration(change.Current.Progress) * time.Millisecond)
+		t.count(switchedAt)
+		t.check()
+	}
+	t.watch(change.Current)
+}
+
+// watch starts watching a new item, which has not been listened to yet
+func (t *Tracker) watch(current *player.CurrentlyPlaying) {
+	t.watching = true
+	t.last = nil
+	t.listened = 0
+	t.done = false
+	if current != nil {
+		t.started = current.FetchedAt.Add(-time.Duration(current.Progress) * time.Millisecond)
+	}
+}
+
+// count adds the time since the last poll to the listening time if the
+// watched track was playing then
+func (t *Tracker) count(until time.Time) {
+	if !t.last.IsPlaying || t.last.FetchedAt.IsZero() {
+		return
+	}
+	if elapsed := until.Sub(t.last.FetchedAt); elapsed > 0 {
+		t.listened += elapsed
+	}
+}
+
+// check scrobbles the watched track once it has been listened to long enough
+func (t *Tracker) check() {
+	current := t.last
+	if t.done || current == nil || current.Track.URI == "" || current.IsEpisode() {
+		return
+	}
+	threshold, ok := threshold(current)
+	if !ok || t.listened < threshold {
+		return
+	}
+	t.done = true
+
+	track := Track{
+		Title:     current.Track.Name,
+		Album:     current.Track.Album.Name,
+		Duration:  time.Duration(current.Track.Duration) * time.Millisecond,
+		StartedAt: t.started,
+	}
+	if len(current.Track.Artists) > 0 {
+		track.Artist = current.Track.Artists[0].Name
+	}
+
+	go t.scrobbler.Scrobble(track)
+}
+
+// threshold returns how long a track must be listened to to count, and
+// false for tracks too short to scrobble
+func threshold(current *player.CurrentlyPlaying) (time.Duration, bool) {
+	duration := time.Duration(current.Track.Duration) * time.Millisecond
+	if duration < minDuration {
+		return 0, false
+	}
+
+	threshold := duration / 2
+	if threshold > maxThreshold {
+		threshold = maxThreshold
+	}
+	return threshold, true
+}
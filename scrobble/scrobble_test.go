@@ -0,0 +1,145 @@
+// scrobble/scrobble_test.go
+package scrobble
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// recorder is a Scrobbler that records what it was given
+type recorder struct {
+	mu     sync.Mutex
+	tracks []Track
+	sent   chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{sent: make(chan struct{}, 10)}
+}
+
+func (r *recorder) Scrobble(track Track) error {
+	r.mu.Lock()
+	r.tracks = append(r.tracks, track)
+	r.mu.Unlock()
+	r.sent <- struct{}{}
+	return nil
+}
+
+// scrobbled returns the tracks scrobbled once the tracker had time to
+// submit want of them
+func (r *recorder) scrobbled(t *testing.T, want int) []Track {
+	t.Helper()
+	for i := 0; i < want; i++ {
+		select {
+		case <-r.sent:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("scrobbled %d tracks, want %d", i, want)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tracks
+}
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// poll returns a playback state of a three minute track, fetched at the
+// given time since start
+func poll(uri string, at time.Duration, progressMs int, playing bool) *player.CurrentlyPlaying {
+	return &player.CurrentlyPlaying{
+		IsPlaying: playing,
+		Progress:  progressMs,
+		FetchedAt: start.Add(at),
+		Track: player.Track{
+			Name:     "Song " + uri,
+			URI:      uri,
+			Duration: 3 * 60 * 1000,
+			Artists:  []player.Artist{{Name: "Artist"}},
+		},
+	}
+}
+
+func TestTrackerCountsListeningTime(t *testing.T) {
+	r := newRecorder()
+	tracker := NewTracker(r)
+
+	// Seeking past the middle right away is not listening
+	tracker.Observe(poll("a", 0, 0, true))
+	tracker.Observe(poll("a", 5*time.Second, 150000, true))
+	// Paused for a long time
+	tracker.Observe(poll("a", 10*time.Second, 155000, false))
+	tracker.Observe(poll("a", 5*time.Minute, 155000, false))
+	if got := r.scrobbled(t, 0); len(got) != 0 {
+		t.Fatalf("scrobbled %+v after 10s of listening", got)
+	}
+
+	// Back to the start, then play through half of it
+	tracker.Observe(poll("a", 5*time.Minute, 0, true))
+	tracker.Observe(poll("a", 6*time.Minute, 60000, true))
+	tracker.Observe(poll("a", 6*time.Minute+20*time.Second, 80000, true))
+
+	tracks := r.scrobbled(t, 1)
+	if tracks[0].Title != "Song a" || tracks[0].Artist != "Artist" || !tracks[0].StartedAt.Equal(start) {
+		t.Errorf("scrobbled %+v", tracks[0])
+	}
+
+	// Only once per play
+	tracker.Observe(poll("a", 7*time.Minute, 120000, true))
+	if got := r.scrobbled(t, 0); len(got) != 1 {
+		t.Errorf("scrobbled %d times, want once", len(got))
+	}
+}
+
+func TestTrackerTrackChange(t *testing.T) {
+	r := newRecorder()
+	tracker := NewTracker(r)
+
+	tracker.Observe(poll("a", 0, 0, true))
+	tracker.Observe(poll("a", 85*time.Second, 85000, true))
+
+	// b started 90s in, so a played for its last 5s before the change
+	b := poll("b", 100*time.Second, 10000, true)
+	tracker.TrackChanged(player.TrackChange{Previous: poll("a", 85*time.Second, 85000, true), Current: b})
+	tracker.Observe(b)
+	if got := r.scrobbled(t, 1); got[0].Title != "Song a" {
+		t.Errorf("scrobbled %+v, want a", got[0])
+	}
+
+	// Time before the change does not count for b
+	tracker.Observe(poll("b", 150*time.Second, 60000, true))
+	if got := r.scrobbled(t, 0); len(got) != 1 {
+		t.Errorf("scrobbled %+v after 50s of b", got)
+	}
+	tracker.Observe(poll("b", 200*time.Second, 110000, true))
+	got := r.scrobbled(t, 1)
+	if len(got) != 2 || got[1].Title != "Song b" || !got[1].StartedAt.Equal(start.Add(90*time.Second)) {
+		t.Errorf("scrobbled %+v, want b started 90s in", got)
+	}
+}
+
+func TestTrackerSkipsShortAndEpisodes(t *testing.T) {
+	r := newRecorder()
+	tracker := NewTracker(r)
+
+	short := poll("short", 0, 0, true)
+	short.Track.Duration = 20000
+	tracker.Observe(short)
+	later := *short
+	later.FetchedAt = start.Add(time.Minute)
+	tracker.Observe(&later)
+
+	episode := poll("spotify:episode:x", time.Minute, 0, true)
+	episode.Type = "episode"
+	tracker.TrackChanged(player.TrackChange{Previous: &later, Current: episode})
+	tracker.Observe(episode)
+	end := *episode
+	end.FetchedAt, end.Progress = start.Add(10*time.Minute), 540000
+	tracker.Observe(&end)
+
+	if got := r.scrobbled(t, 0); len(got) != 0 {
+		t.Errorf("scrobbled %+v", got)
+	}
+}
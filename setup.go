@@ -0,0 +1,176 @@
+// setup.go
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mesyrob/spotify-tmux/config"
+)
+
+// setupIntro explains how to obtain credentials before prompting for them
+const setupIntro = `spotify-tmux setup
+
+spotify-tmux talks to Spotify through your own Spotify app:
+
+  1. Open https://developer.spotify.com/dashboard and log in.
+  2. Click "Create app" and give it any name and description.
+  3. Add the redirect URI shown below to the app's settings
+     (it must match exactly).
+  4. Copy the app's Client ID, and its Client Secret unless you log in
+     with PKCE.
+
+Press Enter to keep the value shown in brackets.
+`
+
+// runSetup interactively writes config.json and authenticates
+func runSetup(args []string) int {
+	cfg, err := config.LoadFile()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read existing config: %v\n", err)
+		return 1
+	}
+
+	creds, err := config.LoadCredentials()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read existing credentials: %v\n", err)
+		return 1
+	}
+	if creds != nil {
+		cfg.ClientID, cfg.ClientSecret = creds.ClientID, creds.ClientSecret
+	}
+
+	fmt.Print(setupIntro, "\n")
+
+	in := bufio.NewReader(os.Stdin)
+
+	cfg.RedirectURI, err = prompt(in, "Redirect URI", cfg.RedirectURI)
+	if err != nil {
+		return setupAborted(err)
+	}
+	if u, err := url.Parse(cfg.RedirectURI); err != nil || u.Scheme == "" || u.Host == "" {
+		fmt.Fprintf(os.Stderr, "%q is not a valid redirect URI\n", cfg.RedirectURI)
+		return 1
+	}
+
+	cfg.ClientID, err = prompt(in, "Client ID", cfg.ClientID)
+	if err != nil {
+		return setupAborted(err)
+	}
+
+	cfg.UsePKCE, err = promptYesNo(in, "Log in with PKCE, which needs no Client Secret", cfg.UsePKCE)
+	if err != nil {
+		return setupAborted(err)
+	}
+
+	if !cfg.UsePKCE {
+		cfg.ClientSecret, err = promptSecret(in, "Client Secret", cfg.ClientSecret)
+		if err != nil {
+			return setupAborted(err)
+		}
+	}
+
+	if !cfg.HasCredentials() {
+		fmt.Fprintln(os.Stderr, "Client ID is required, and Client Secret unless use_pkce is set")
+		return 1
+	}
+
+	// Keep the secret out of the shareable config file
+	newCreds := config.Credentials{ClientID: cfg.ClientID, ClientSecret: cfg.ClientSecret}
+	if err := config.SaveCredentials(newCreds); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save credentials: %v\n", err)
+		return 1
+	}
+
+	fileCfg := cfg
+	fileCfg.ClientID, fileCfg.ClientSecret = "", ""
+	if err := config.Save(fileCfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save configuration: %v\n", err)
+		return 1
+	}
+
+	path, _ := config.Path()
+	credsPath, _ := config.CredentialsPath()
+	fmt.Printf("\nSaved configuration to %s\nSaved credentials to %s\n\n", path, credsPath)
+
+	authService, err := newAuthService(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := authService.Authenticate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Authentication failed: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("\nAll set! Run spotify-tmux to start the player.")
+	return 0
+}
+
+// prompt asks for a value, returning def when the answer is empty
+func prompt(in *bufio.Reader, label, def string) (string, error) {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", label, def)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+	return readAnswer(in, def)
+}
+
+// promptSecret is like prompt but does not echo the current value
+func promptSecret(in *bufio.Reader, label, def string) (string, error) {
+	if def != "" {
+		label += " (already set)"
+	}
+	fmt.Printf("%s: ", label)
+	return readAnswer(in, def)
+}
+
+// promptYesNo asks a yes/no question, returning def when the answer is empty
+func promptYesNo(in *bufio.Reader, label string, def bool) (bool, error) {
+	choices := "y/N"
+	if def {
+		choices = "Y/n"
+	}
+
+	for {
+		fmt.Printf("%s? [%s]: ", label, choices)
+		answer, err := readAnswer(in, "")
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Println("Please answer y or n.")
+	}
+}
+
+// readAnswer reads one line of input, returning def when it is empty
+func readAnswer(in *bufio.Reader, def string) (string, error) {
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// setupAborted reports an input error and returns the exit code
+func setupAborted(err error) int {
+	fmt.Fprintf(os.Stderr, "\nSetup aborted: %v\n", err)
+	return 1
+}
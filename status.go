@@ -0,0 +1,111 @@
+// status.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mesyrob/spotify-tmux/auth"
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// runStatus implements -status: it prints the current track on one line,
+// e.g. for #(spotify-tmux -status) in a tmux status bar. It never starts
+// the login flow. Only authentication problems exit non-zero; other
+// failures print an empty line so the status bar just stays blank.
+func runStatus(format string) int {
+	playerService, err := savedTokenPlayer(player.WithStatusFormat(format))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	info, err := playerService.FormatTrackInfo()
+	if err != nil {
+		if isAuthError(err) {
+			fmt.Fprintf(os.Stderr, "Not logged in, run spotify-tmux once: %v\n", err)
+			return 1
+		}
+		fmt.Println()
+		return 0
+	}
+
+	fmt.Println(info)
+	return 0
+}
+
+// isAuthError reports whether err means the token was rejected or could
+// not be refreshed
+func isAuthError(err error) bool {
+	return errors.Is(err, auth.ErrReauthRequired) || errors.Is(err, player.ErrUnauthorized)
+}
+
+// nowPlaying is the JSON printed by -json. Fields are only ever added, so
+// scripts can rely on them. Everything but is_playing is left out when
+// nothing is playing.
+type nowPlaying struct {
+	IsPlaying bool `json:"is_playing"`
+	// Type is "track" or "episode"
+	Type       string   `json:"type,omitempty"`
+	URI        string   `json:"uri,omitempty"`
+	Track      string   `json:"track,omitempty"`
+	Artists    []string `json:"artists,omitempty"`
+	Album      string   `json:"album,omitempty"`
+	ProgressMs int      `json:"progress_ms,omitempty"`
+	DurationMs int      `json:"duration_ms,omitempty"`
+	// Device is the active device, absent when there is none
+	Device *nowPlayingDevice `json:"device,omitempty"`
+}
+
+// nowPlayingDevice is the device part of nowPlaying
+type nowPlayingDevice struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// VolumePercent is absent for devices without volume control
+	VolumePercent *int `json:"volume_percent,omitempty"`
+}
+
+// runJSON implements -json: it prints the playback state as JSON. Nothing
+// playing is {"is_playing": false}, not an error.
+func runJSON() int {
+	playerService, err := savedTokenPlayer()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	state, err := playerService.GetPlaybackState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get the playback state: %v\n", err)
+		return 1
+	}
+
+	out := nowPlaying{IsPlaying: state.IsPlaying}
+	if item := state.Track; item.URI != "" {
+		out.Type = item.Type
+		out.URI = item.URI
+		out.Track = item.Name
+		out.Album = item.Album.Name
+		out.ProgressMs = state.Progress
+		out.DurationMs = item.Duration
+		for _, artist := range item.Artists {
+			out.Artists = append(out.Artists, artist.Name)
+		}
+		if len(out.Artists) == 0 && item.Show != nil {
+			out.Artists = []string{item.Show.Name}
+		}
+	}
+	if d := state.Device; d != nil {
+		out.Device = &nowPlayingDevice{Name: d.Name, Type: d.Type, VolumePercent: d.VolumePercent}
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode the playback state: %v\n", err)
+		return 1
+	}
+	fmt.Println(string(data))
+	return 0
+}
@@ -0,0 +1,150 @@
+// ui/albumart.go
+package ui
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mesyrob/spotify-tmux/player"
+	"github.com/rivo/tview"
+)
+
+const (
+	// albumArtRows is the height of the album art pane. Each row holds two
+	// pixels, so the cover is drawn 2*albumArtRows cells wide.
+	albumArtRows = 8
+	// albumArtMaxWidth is the widest cover image downloaded
+	albumArtMaxWidth = 300
+	// albumArtMinColors is the fewest colors a terminal needs to show art
+	albumArtMinColors = 256
+)
+
+// albumArtClient downloads cover images
+var albumArtClient = &http.Client{Timeout: 10 * time.Second}
+
+// albumArt draws the cover of the current track with half-block
+// characters. It draws nothing on terminals with fewer than
+// albumArtMinColors colors.
+type albumArt struct {
+	*tview.Box
+
+	mu sync.Mutex
+	// trackURI is the track the cover belongs to, imageURL where it was
+	// downloaded from
+	trackURI string
+	imageURL string
+	img      image.Image
+}
+
+// newAlbumArt creates an empty album art pane
+func newAlbumArt() *albumArt {
+	return &albumArt{Box: tview.NewBox()}
+}
+
+// Draw implements tview.Primitive
+func (a *albumArt) Draw(screen tcell.Screen) {
+	a.Box.DrawForSubclass(screen, a)
+	if screen.Colors() < albumArtMinColors {
+		return
+	}
+
+	a.mu.Lock()
+	img := a.img
+	a.mu.Unlock()
+	if img == nil {
+		return
+	}
+
+	x, y, width, height := a.GetInnerRect()
+	size := width
+	if size > 2*height {
+		size = 2 * height
+	}
+	if size <= 0 {
+		return
+	}
+	x += (width - size) / 2
+
+	// The upper half block takes the foreground color, the lower half the
+	// background color
+	bounds := img.Bounds()
+	pixel := func(px, py int) tcell.Color {
+		r, g, b, _ := img.At(
+			bounds.Min.X+px*bounds.Dx()/size,
+			bounds.Min.Y+py*bounds.Dy()/size,
+		).RGBA()
+		return tcell.NewRGBColor(int32(r>>8), int32(g>>8), int32(b>>8))
+	}
+	for row := 0; row < size/2; row++ {
+		for col := 0; col < size; col++ {
+			style := tcell.StyleDefault.
+				Foreground(pixel(col, 2*row)).
+				Background(pixel(col, 2*row+1))
+			screen.SetContent(x+col, y+row, '▀', nil, style)
+		}
+	}
+}
+
+// updateAlbumArt downloads the cover when the track changes. It returns
+// immediately, the pane is redrawn once the download finished.
+func (u *UI) updateAlbumArt(current *player.CurrentlyPlaying) {
+	a := u.albumArt
+	trackURI := current.Track.URI
+	imageURL := current.Track.Album.BestImageURL(albumArtMaxWidth)
+
+	a.mu.Lock()
+	if trackURI == a.trackURI {
+		a.mu.Unlock()
+		return
+	}
+	a.trackURI = trackURI
+	// Tracks of the same album share the cover
+	if imageURL == a.imageURL {
+		a.mu.Unlock()
+		return
+	}
+	a.imageURL = imageURL
+	a.img = nil
+	a.mu.Unlock()
+
+	if imageURL == "" {
+		u.app.QueueUpdateDraw(func() {})
+		return
+	}
+
+	go func() {
+		img, err := fetchImage(imageURL)
+		if err != nil {
+			return
+		}
+
+		a.mu.Lock()
+		if a.imageURL == imageURL {
+			a.img = img
+		}
+		a.mu.Unlock()
+		u.app.QueueUpdateDraw(func() {})
+	}()
+}
+
+// fetchImage downloads and decodes a JPEG or PNG image
+func fetchImage(url string) (image.Image, error) {
+	resp, err := albumArtClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image: %s", resp.Status)
+	}
+
+	img, _, err := image.Decode(resp.Body)
+	return img, err
+}
@@ -0,0 +1,36 @@
+// ui/bookmarks.go
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mesyrob/spotify-tmux/config"
+)
+
+// bookmarkHelp lists the bookmarks with their keys for the help rows
+func bookmarkHelp(bookmarks []config.Bookmark) string {
+	entries := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		label := b.Label
+		if label == "" {
+			label = b.URI
+		}
+		entries[i] = fmt.Sprintf("%d = %s", i+1, label)
+	}
+	return "Bookmarks: " + strings.Join(entries, ", ")
+}
+
+// playBookmark plays the bookmark with the given 1-based number
+func (u *UI) playBookmark(n int) {
+	if n < 1 || n > len(u.config.Bookmarks) {
+		return
+	}
+	if !u.requirePremium() {
+		return
+	}
+
+	u.runAction(func() error {
+		return u.player.PlayURI(u.config.Bookmarks[n-1].URI, "")
+	})
+}
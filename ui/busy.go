@@ -0,0 +1,77 @@
+// ui/busy.go
+package ui
+
+import "time"
+
+// spinnerInterval is how often the busy indicator advances
+const spinnerInterval = 100 * time.Millisecond
+
+// Busy indicator frames, with an ASCII fallback for limited terminals
+var (
+	spinnerFrames      = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	asciiSpinnerFrames = []string{"|", "/", "-", "\\"}
+)
+
+// runAction runs a player action in the background, showing the busy
+// indicator until it completes, so a slow network does not freeze the UI.
+// Errors are shown; otherwise the player polls right away, so the new state
+// shows up without waiting for the next poll.
+func (u *UI) runAction(action func() error) {
+	u.mu.Lock()
+	u.busy++
+	start := !u.spinning
+	u.spinning = true
+	u.mu.Unlock()
+	if start {
+		go u.spin()
+	}
+
+	go func() {
+		err := action()
+
+		u.mu.Lock()
+		u.busy--
+		u.mu.Unlock()
+
+		if err != nil {
+			u.showError(err)
+			return
+		}
+		u.player.Refresh()
+	}()
+}
+
+// spin animates the busy indicator until no action is in flight
+func (u *UI) spin() {
+	frames := spinnerFrames
+	if u.config.ASCIIButtons {
+		frames = asciiSpinnerFrames
+	}
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		u.mu.Lock()
+		busy := u.busy > 0
+		u.spinning = busy
+		u.mu.Unlock()
+
+		glyph := ""
+		if busy {
+			glyph = frames[frame%len(frames)]
+		}
+		u.app.QueueUpdateDraw(func() {
+			u.busyText.SetText(glyph)
+		})
+		if !busy {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-u.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// ui/buttons.go
+package ui
+
+import "github.com/mesyrob/spotify-tmux/config"
+
+// unicodeLabels are the default button labels
+var unicodeLabels = config.ButtonLabels{
+	Previous: "◀ Previous",
+	Play:     "▶ Play",
+	Pause:    "⏸ Pause",
+	Next:     "Next ▶",
+}
+
+// asciiLabels are the default button labels for terminals whose fonts lack
+// the symbols
+var asciiLabels = config.ButtonLabels{
+	Previous: "<< Prev",
+	Play:     "> Play",
+	Pause:    "|| Pause",
+	Next:     "Next >>",
+}
+
+// buttonLabels returns the configured labels with defaults filled in
+func buttonLabels(cfg config.Config) config.ButtonLabels {
+	defaults := unicodeLabels
+	if cfg.ASCIIButtons {
+		defaults = asciiLabels
+	}
+
+	labels := cfg.ButtonLabels
+	if labels.Previous == "" {
+		labels.Previous = defaults.Previous
+	}
+	if labels.Play == "" {
+		labels.Play = defaults.Play
+	}
+	if labels.Pause == "" {
+		labels.Pause = defaults.Pause
+	}
+	if labels.Next == "" {
+		labels.Next = defaults.Next
+	}
+	return labels
+}
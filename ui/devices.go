@@ -0,0 +1,101 @@
+// ui/devices.go
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mesyrob/spotify-tmux/player"
+	"github.com/rivo/tview"
+)
+
+// devicePage is the page name of the device picker
+const devicePage = "devices"
+
+// showDevicePicker lists the available devices and transfers playback to
+// the one selected
+func (u *UI) showDevicePicker() {
+	if !u.requirePremium() {
+		return
+	}
+
+	go func() {
+		devices, err := u.player.ListDevices()
+		if err != nil {
+			u.showError(err)
+			return
+		}
+		u.app.QueueUpdateDraw(func() {
+			u.openDevicePicker(devices)
+		})
+	}()
+}
+
+// openDevicePicker shows the device picker modal.
+// It must run on the UI goroutine.
+func (u *UI) openDevicePicker(devices []player.Device) {
+	list := tview.NewList().
+		ShowSecondaryText(false)
+
+	closePicker := func() {
+		u.pages.RemovePage(devicePage)
+		u.app.SetFocus(u.grid)
+	}
+
+	if len(devices) == 0 {
+		list.AddItem("No devices found", "", 0, closePicker)
+	}
+	for _, device := range devices {
+		device := device
+		label := fmt.Sprintf("%s (%s)", device.Name, device.Type)
+		if device.IsActive {
+			label = "● " + label
+		} else {
+			label = "  " + label
+		}
+		if device.IsRestricted {
+			label += " - cannot be controlled"
+		}
+
+		list.AddItem(tview.Escape(label), "", 0, func() {
+			closePicker()
+			if device.IsActive {
+				return
+			}
+			u.runAction(func() error {
+				return u.player.TransferPlayback(device.ID, false)
+			})
+		})
+	}
+
+	list.SetDoneFunc(closePicker)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' || event.Rune() == 'd' {
+			closePicker()
+			return nil
+		}
+		return event
+	})
+	list.SetBorder(true).SetTitle(" Devices ")
+
+	height := len(devices) + 2
+	if len(devices) == 0 {
+		height = 3
+	}
+	u.pages.AddPage(devicePage, centered(list, 50, height), true, true)
+	u.app.SetFocus(list)
+}
+
+// deviceHeader formats the header line naming the device that receives
+// commands, with its volume if it has volume control
+func deviceHeader(device *player.Device) string {
+	if device == nil {
+		return "[yellow]No active device[white]"
+	}
+
+	header := fmt.Sprintf("%s (%s)", tview.Escape(device.Name), device.Type)
+	if device.VolumePercent != nil {
+		header += fmt.Sprintf(" · %d%%", *device.VolumePercent)
+	}
+	return header
+}
@@ -0,0 +1,64 @@
+// ui/features.go
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// maxCachedFeatures bounds the audio features cache, which is emptied when
+// it grows past this
+const maxCachedFeatures = 500
+
+// featuresBadge returns the tempo and key shown after the track info,
+// starting a lookup the first time a track plays. Lookups are cached by
+// track ID, also when they failed, so each track costs at most one request.
+func (u *UI) featuresBadge(current *player.CurrentlyPlaying) string {
+	if current.Track.URI == "" || current.IsEpisode() {
+		return ""
+	}
+	kind, id, err := player.ParseURI(current.Track.URI)
+	if err != nil || kind != "track" {
+		return ""
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	features, ok := u.features[id]
+	if !ok {
+		if u.features == nil || len(u.features) >= maxCachedFeatures {
+			u.features = make(map[string]*player.AudioFeatures)
+		}
+		// Marks the lookup as started
+		u.features[id] = nil
+		go u.fetchFeatures(id)
+		return ""
+	}
+	if features == nil {
+		return ""
+	}
+
+	badge := fmt.Sprintf("  [gray]%d BPM", int(math.Round(features.Tempo)))
+	if key := features.KeyName(); key != "" {
+		badge += fmt.Sprintf(" · %s (%s)", key, features.Camelot())
+	}
+	return badge + "[white]"
+}
+
+// fetchFeatures looks up the audio features of a track. Failures, e.g.
+// for apps Spotify no longer serves them to, leave the badge hidden.
+func (u *UI) fetchFeatures(id string) {
+	features, err := u.player.GetAudioFeatures(id)
+	if err != nil {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.features[id]; ok {
+		u.features[id] = features
+	}
+}
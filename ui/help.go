@@ -0,0 +1,55 @@
+// ui/help.go
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// helpPage is the page name of the key help overlay
+const helpPage = "help"
+
+// keyHelpText lists every action with its keys, one per line, unbound
+// actions included
+func (u *UI) keyHelpText() string {
+	keys := u.keymap.actionKeys()
+
+	var lines []string
+	for _, action := range u.keymap.actions {
+		bound := keys[action.name]
+		if bound == "" {
+			bound = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%-9s %s", bound, action.help))
+	}
+	if len(u.config.Bookmarks) > 0 {
+		lines = append(lines, "", bookmarkHelp(u.config.Bookmarks))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// showKeyHelp opens an overlay listing all key bindings. ? or Esc closes
+// it. It must run on the UI goroutine.
+func (u *UI) showKeyHelp() {
+	text := u.keyHelpText()
+
+	view := tview.NewTextView().
+		SetText(text).
+		SetWordWrap(true)
+	view.SetBorder(true).SetTitle(" Keys (? or Esc to close) ")
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == '?' {
+			u.pages.RemovePage(helpPage)
+			u.app.SetFocus(u.grid)
+			return nil
+		}
+		return event
+	})
+
+	u.pages.AddPage(helpPage, centered(view, 50, strings.Count(text, "\n")+3), true, true)
+	u.app.SetFocus(view)
+}
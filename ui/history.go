@@ -0,0 +1,149 @@
+// ui/history.go
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mesyrob/spotify-tmux/player"
+	"github.com/rivo/tview"
+)
+
+// replayLastHeard plays the most recent track from the listening history
+// that is not the one playing now, in its context when it had one. Unlike
+// previous this is not limited to the current context.
+func (u *UI) replayLastHeard() {
+	if !u.requirePremium() || !u.runGrant(&u.historyGrant) {
+		return
+	}
+
+	current := u.currentState()
+	u.runAction(func() error {
+		history, err := u.player.GetRecentlyPlayed(20, 0)
+		if err != nil {
+			return err
+		}
+
+		entry := lastHeard(history.Items, current)
+		if entry == nil {
+			return errors.New("no earlier track in your listening history")
+		}
+
+		u.showNotice("Replaying " + entry.Track.DisplayName())
+
+		contextURI := ""
+		if entry.Context != nil {
+			contextURI = entry.Context.URI
+		}
+		return u.player.PlayURI(contextURI, entry.Track.URI)
+	})
+}
+
+// lastHeard returns the newest history entry that is not the playing item
+func lastHeard(items []player.PlayHistory, current *player.CurrentlyPlaying) *player.PlayHistory {
+	for i := range items {
+		if current != nil && items[i].Track.URI == current.Track.URI {
+			continue
+		}
+		return &items[i]
+	}
+	return nil
+}
+
+// historyPage is the page name of the recently played list
+const historyPage = "history"
+
+// historyPageSize is how many tracks the history list loads at a time
+const historyPageSize = 20
+
+// showHistory lists the recently played tracks and replays the one selected
+func (u *UI) showHistory() {
+	if !u.requirePremium() || !u.runGrant(&u.historyGrant) {
+		return
+	}
+
+	go func() {
+		history, err := u.player.GetRecentlyPlayed(historyPageSize, 0)
+		if err != nil {
+			u.showError(err)
+			return
+		}
+		u.app.QueueUpdateDraw(func() {
+			u.openHistory(history)
+		})
+	}()
+}
+
+// openHistory shows the recently played list, newest first. Older tracks
+// are fetched with the page's cursor when "Load earlier" is selected.
+// It must run on the UI goroutine.
+func (u *UI) openHistory(first *player.RecentlyPlayed) {
+	list := tview.NewList().
+		ShowSecondaryText(false)
+
+	closeHistory := func() {
+		u.pages.RemovePage(historyPage)
+		u.app.SetFocus(u.grid)
+	}
+
+	var addPage func(history *player.RecentlyPlayed)
+	addPage = func(history *player.RecentlyPlayed) {
+		for _, entry := range history.Items {
+			track := entry.Track
+			label := fmt.Sprintf("%s  %s", playedAt(entry.PlayedAt, time.Now()), track.DisplayName())
+			list.AddItem(tview.Escape(label), "", 0, func() {
+				closeHistory()
+				u.runAction(func() error {
+					return u.player.PlayTracks([]string{track.URI})
+				})
+			})
+		}
+
+		before := history.Cursor()
+		if before == 0 || len(history.Items) == 0 {
+			return
+		}
+		list.AddItem("Load earlier", "", 0, func() {
+			list.RemoveItem(list.GetItemCount() - 1)
+			go func() {
+				older, err := u.player.GetRecentlyPlayedBefore(historyPageSize, before)
+				if err != nil {
+					u.showError(err)
+					return
+				}
+				u.app.QueueUpdateDraw(func() {
+					addPage(older)
+				})
+			}()
+		})
+	}
+
+	addPage(first)
+	if list.GetItemCount() == 0 {
+		list.AddItem("Nothing played recently", "", 0, closeHistory)
+	}
+
+	list.SetDoneFunc(closeHistory)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' || event.Rune() == 'r' {
+			closeHistory()
+			return nil
+		}
+		return event
+	})
+	list.SetBorder(true).SetTitle(" Recently played ")
+
+	u.pages.AddPage(historyPage, centered(list, playlistWidth, playlistHeight), true, true)
+	u.app.SetFocus(list)
+}
+
+// playedAt formats when a track was played, with the date unless it was today
+func playedAt(t, now time.Time) string {
+	t = t.Local()
+	if y, m, d := t.Date(); y == now.Year() && m == now.Month() && d == now.Day() {
+		return t.Format("15:04")
+	}
+	return t.Format("Jan 2 15:04")
+}
@@ -0,0 +1,53 @@
+// ui/interpolate.go
+package ui
+
+import (
+	"time"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// frameInterval is how often the progress is redrawn between polls
+const frameInterval = 250 * time.Millisecond
+
+// estimateProgress returns a copy of current with the progress advanced by
+// the time passed since it was fetched, capped at the duration. Paused and
+// empty states are returned unchanged.
+func estimateProgress(current *player.CurrentlyPlaying, now time.Time) *player.CurrentlyPlaying {
+	if !current.IsPlaying || current.Track.URI == "" || current.FetchedAt.IsZero() {
+		return current
+	}
+
+	elapsed := now.Sub(current.FetchedAt)
+	if elapsed <= 0 {
+		return current
+	}
+
+	estimate := *current
+	estimate.Progress += int(elapsed.Milliseconds())
+	if estimate.Track.Duration > 0 && estimate.Progress > estimate.Track.Duration {
+		estimate.Progress = estimate.Track.Duration
+	}
+	return &estimate
+}
+
+// redrawProgress redraws the track info and progress bar with the progress
+// estimated from the last poll, so they advance smoothly while playing.
+// Each poll starts the estimate afresh.
+func (u *UI) redrawProgress() {
+	u.mu.Lock()
+	current, errorShown := u.current, u.errorShown
+	u.mu.Unlock()
+	if current == nil || !current.IsPlaying || errorShown {
+		return
+	}
+
+	estimate := estimateProgress(current, time.Now())
+	info := u.infoLine(estimate)
+	u.updateLyrics(estimate)
+
+	u.app.QueueUpdateDraw(func() {
+		u.updateProgressBar(estimate)
+		u.infoText.SetText(info)
+	})
+}
@@ -0,0 +1,161 @@
+// ui/keymap.go
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mesyrob/spotify-tmux/config"
+)
+
+// keyAction is something a key can be bound to
+type keyAction struct {
+	// name identifies the action in the keymap, help describes it in the
+	// shortcuts row (empty to leave it out)
+	name string
+	help string
+	run  func(u *UI)
+}
+
+// keyActions lists every action in the order the shortcuts row shows them
+var keyActions = []keyAction{
+	{"play_pause", "play/pause", (*UI).playPause},
+	{"next", "next", (*UI).next},
+	{"previous", "previous", (*UI).previous},
+	{"replay_last", "replay last heard", (*UI).replayLastHeard},
+	{"history", "history", (*UI).showHistory},
+	{"seek_back", "seek back", func(u *UI) { u.seekBy(-1) }},
+	{"seek_forward", "seek forward", func(u *UI) { u.seekBy(1) }},
+	{"volume_down", "volume down", func(u *UI) { u.changeVolume(-1) }},
+	{"volume_up", "volume up", func(u *UI) { u.changeVolume(1) }},
+	{"podcast_back", "podcast back", func(u *UI) { u.podcastSkip(-1) }},
+	{"podcast_forward", "podcast forward", func(u *UI) { u.podcastSkip(1) }},
+	{"speed_down", "slower", func(u *UI) { u.changeSpeed(-1) }},
+	{"speed_up", "faster", func(u *UI) { u.changeSpeed(1) }},
+	{"clear_queue", "clear queue", (*UI).clearQueue},
+	{"pin", "pin", (*UI).pin},
+	{"unpin", "unpin", (*UI).unpin},
+	{"stop_at_end", "stop at end", (*UI).toggleStopAtEnd},
+	{"ab_loop", "A-B loop", (*UI).markLoop},
+	{"seek_to", "seek to time", (*UI).promptSeek},
+	{"like", "like", (*UI).toggleLike},
+	{"shuffle", "shuffle", (*UI).toggleShuffle},
+	{"repeat", "repeat", (*UI).cycleRepeat},
+	{"devices", "devices", (*UI).showDevicePicker},
+	{"search", "search", (*UI).showSearch},
+	{"queue", "queue", (*UI).toggleQueue},
+	{"lyrics", "lyrics", (*UI).toggleLyrics},
+	{"playlists", "playlists", (*UI).showPlaylists},
+	{"stats", "stats", (*UI).toggleStats},
+	{"help", "all keys", (*UI).showKeyHelp},
+	{"shortcuts", "hide this line", (*UI).toggleShortcuts},
+	{"login", "", (*UI).reauthenticate},
+	{"quit", "quit", (*UI).Stop},
+}
+
+// bookmarkActions returns the actions playing bookmarks 1 to 9, which are
+// listed in their own row
+func bookmarkActions() []keyAction {
+	actions := make([]keyAction, 9)
+	for i := range actions {
+		n := i + 1
+		actions[i] = keyAction{
+			name: fmt.Sprintf("bookmark_%d", n),
+			run:  func(u *UI) { u.playBookmark(n) },
+		}
+	}
+	return actions
+}
+
+// keymap maps keys to the actions they run
+type keymap struct {
+	keys map[rune]keyAction
+	// actions are all actions in the order of keyActions, bound or not
+	actions []keyAction
+}
+
+// newKeymap builds the keymap from the configured bindings, which were
+// already validated when loading the config
+func newKeymap(cfg config.Config) keymap {
+	bindings, err := cfg.KeyBindings()
+	if err != nil {
+		bindings = config.DefaultKeyBindings()
+	}
+
+	actions := make(map[string]keyAction)
+	for _, action := range append(keyActions, bookmarkActions()...) {
+		actions[action.name] = action
+	}
+
+	km := keymap{keys: make(map[rune]keyAction), actions: keyActions}
+	for _, binding := range bindings {
+		if action, ok := actions[binding.Action]; ok {
+			km.keys[binding.Key] = action
+		}
+	}
+	return km
+}
+
+// handle runs the action bound to a key event, returning nil if there was
+// one. Text input lives on other pages, so typing there never gets here.
+func (km keymap) handle(u *UI, event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune {
+		return event
+	}
+	action, ok := km.keys[event.Rune()]
+	if !ok {
+		return event
+	}
+	action.run(u)
+	return nil
+}
+
+// keyName is how a key is shown in the shortcuts row
+func keyName(key rune) string {
+	if key == ' ' {
+		return "space"
+	}
+	return string(key)
+}
+
+// actionKeys returns the keys bound to each action, e.g. "p/space" for
+// play_pause
+func (km keymap) actionKeys() map[string]string {
+	keys := make(map[string][]rune)
+	for key, action := range km.keys {
+		keys[action.name] = append(keys[action.name], key)
+	}
+
+	names := make(map[string]string, len(keys))
+	for action, bound := range keys {
+		// Keep the order stable, with space after the printable keys
+		sort.Slice(bound, func(i, j int) bool {
+			if (bound[i] == ' ') != (bound[j] == ' ') {
+				return bound[j] == ' '
+			}
+			return bound[i] < bound[j]
+		})
+		parts := make([]string, len(bound))
+		for i, key := range bound {
+			parts[i] = keyName(key)
+		}
+		names[action] = strings.Join(parts, "/")
+	}
+	return names
+}
+
+// shortcutsText lists the bound actions in the order of keyActions, e.g.
+// "Shortcuts: p/space = play/pause, n = next, ..."
+func (km keymap) shortcutsText() string {
+	keys := km.actionKeys()
+
+	var parts []string
+	for _, action := range km.actions {
+		if keys[action.name] != "" && action.help != "" {
+			parts = append(parts, keys[action.name]+" = "+action.help)
+		}
+	}
+	return "Shortcuts: " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,91 @@
+// ui/library.go
+package ui
+
+import (
+	"errors"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// likedState is whether the playing track is in Liked Songs
+type likedState struct {
+	uri   string
+	known bool
+	saved bool
+}
+
+// likeBadge returns the heart shown after the track info, starting a lookup
+// when the track changed. It is empty for episodes and while unknown.
+func (u *UI) likeBadge(current *player.CurrentlyPlaying) string {
+	if current.Track.URI == "" || current.IsEpisode() {
+		return ""
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.liked.uri != current.Track.URI {
+		u.liked = likedState{uri: current.Track.URI}
+		go u.refreshLiked(current.Track.URI)
+		return ""
+	}
+	if !u.liked.known {
+		return ""
+	}
+	if u.liked.saved {
+		return "  [red]♥[white]"
+	}
+	return "  ♡"
+}
+
+// refreshLiked looks up whether a track is saved. Failures, e.g. a token
+// without the library scopes, leave the heart hidden.
+func (u *UI) refreshLiked(uri string) {
+	saved, err := u.player.IsTrackSaved(uri)
+	if err != nil {
+		return
+	}
+	u.setLiked(uri, saved)
+}
+
+// setLiked records the saved state of a track if it is still playing
+func (u *UI) setLiked(uri string, saved bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.liked.uri == uri {
+		u.liked.known = true
+		u.liked.saved = saved
+	}
+}
+
+// toggleLike saves or removes the playing track from Liked Songs and
+// confirms it in the notice row
+func (u *UI) toggleLike() {
+	current := u.currentState()
+	if current == nil || current.Track.URI == "" {
+		u.showError(player.ErrNothingToSave)
+		return
+	}
+	if current.IsEpisode() {
+		u.showError(errors.New("only tracks can be saved to Liked Songs"))
+		return
+	}
+	if !u.runGrant(&u.libraryGrant) {
+		return
+	}
+
+	uri := current.Track.URI
+	u.runAction(func() error {
+		saved, err := u.player.ToggleSaveCurrentTrack()
+		if err != nil {
+			return err
+		}
+		u.setLiked(uri, saved)
+		if saved {
+			u.showNotice("Saved to Liked Songs")
+		} else {
+			u.showNotice("Removed from Liked Songs")
+		}
+		return nil
+	})
+}
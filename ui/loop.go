@@ -0,0 +1,86 @@
+// ui/loop.go
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// abLoop repeats the section between two marks of one item
+type abLoop struct {
+	trackURI string
+	start    int
+	end      int // zero until point B is marked
+}
+
+// String formats the loop bounds for the info line
+func (l *abLoop) String() string {
+	if l.end == 0 {
+		return fmt.Sprintf("loop %s–?", player.FormatDuration(l.start))
+	}
+	return fmt.Sprintf("loop %s–%s", player.FormatDuration(l.start), player.FormatDuration(l.end))
+}
+
+// markLoop marks point A, then point B, then clears the loop
+func (u *UI) markLoop() {
+	if !u.requirePremium() {
+		return
+	}
+
+	current := u.currentState()
+	if current == nil || current.Track.URI == "" {
+		u.showError(player.ErrNothingPlaying)
+		return
+	}
+	if !current.CanSeek() {
+		u.showError(errDisallowed)
+		return
+	}
+
+	u.mu.Lock()
+	switch {
+	case u.loop == nil || u.loop.trackURI != current.Track.URI:
+		u.loop = &abLoop{trackURI: current.Track.URI, start: current.Progress}
+	case u.loop.end == 0:
+		if current.Progress <= u.loop.start {
+			u.mu.Unlock()
+			u.showError(errors.New("point B must come after point A"))
+			return
+		}
+		u.loop.end = current.Progress
+	default:
+		u.loop = nil
+	}
+	u.mu.Unlock()
+
+	go u.redrawInfo()
+}
+
+// checkLoop seeks back to point A once playback passes point B
+func (u *UI) checkLoop(current *player.CurrentlyPlaying) {
+	u.mu.Lock()
+	loop := u.loop
+	u.mu.Unlock()
+
+	if loop == nil || loop.end == 0 || !current.IsPlaying || current.Track.URI != loop.trackURI {
+		return
+	}
+
+	if current.Progress >= loop.end {
+		if err := u.player.Seek(loop.start); err != nil {
+			u.showError(err)
+		}
+	}
+}
+
+// clearLoop drops the loop when another item starts playing
+func (u *UI) clearLoop(change player.TrackChange) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.loop != nil && u.loop.trackURI != change.Current.Track.URI {
+		u.loop = nil
+	}
+}
@@ -0,0 +1,170 @@
+// ui/lyrics.go
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mesyrob/spotify-tmux/lyrics"
+	"github.com/mesyrob/spotify-tmux/player"
+	"github.com/rivo/tview"
+)
+
+// lyricsPanelRows is the height of the lyrics panel
+const lyricsPanelRows = 10
+
+// maxCachedLyrics bounds the lyrics cache, which is emptied when it grows
+// past this
+const maxCachedLyrics = 100
+
+// lyricsEntry is a cached lookup, done once it finished. lyrics is nil
+// when there are none.
+type lyricsEntry struct {
+	lyrics *lyrics.Lyrics
+	done   bool
+}
+
+// lyricsState holds the lyrics by track URI, and the track, lookup state
+// and line the panel shows so it is only redrawn when they change
+type lyricsState struct {
+	cache map[string]lyricsEntry
+	// shown is false until the panel was drawn since it was last opened
+	shown     bool
+	shownURI  string
+	shownDone bool
+	shownLine int
+}
+
+// newLyricsPanel creates the text view showing the lyrics
+func newLyricsPanel() *tview.TextView {
+	panel := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true).
+		SetWrap(true)
+	panel.SetBorder(true).SetTitle(" Lyrics ")
+	return panel
+}
+
+// toggleLyrics shows or hides the lyrics panel
+func (u *UI) toggleLyrics() {
+	u.mu.Lock()
+	u.showLyrics = !u.showLyrics
+	visible := u.showLyrics
+	u.lyricsState.shown = false
+	u.mu.Unlock()
+
+	u.layout()
+	if current := u.currentState(); visible && current != nil {
+		go u.updateLyrics(estimateProgress(current, time.Now()))
+	}
+}
+
+// updateLyrics shows the lyrics of the playing track in the panel, looking
+// them up the first time a track plays. Synced lyrics highlight the line at
+// the current position. It does nothing while the panel is hidden.
+func (u *UI) updateLyrics(current *player.CurrentlyPlaying) {
+	u.mu.Lock()
+	if !u.showLyrics {
+		u.mu.Unlock()
+		return
+	}
+
+	uri := current.Track.URI
+	entry, ok := u.lyricsState.cache[uri]
+	if !ok && uri != "" && !current.IsEpisode() {
+		if u.lyricsState.cache == nil || len(u.lyricsState.cache) >= maxCachedLyrics {
+			u.lyricsState.cache = make(map[string]lyricsEntry)
+		}
+		u.lyricsState.cache[uri] = lyricsEntry{}
+		go u.fetchLyrics(uri, lyricsTrack(current))
+	}
+
+	line := -1
+	var text string
+	switch {
+	case uri == "":
+		text = "[gray]Nothing playing[-]"
+	case current.IsEpisode() || (entry.done && entry.lyrics == nil):
+		text = "[gray]Lyrics unavailable[-]"
+	case !entry.done:
+		text = "[gray]Loading lyrics…[-]"
+	case len(entry.lyrics.Lines) > 0:
+		line = entry.lyrics.LineAt(time.Duration(current.Progress) * time.Millisecond)
+		text = u.syncedLyrics(entry.lyrics.Lines, line)
+	default:
+		text = tview.Escape(entry.lyrics.Plain)
+	}
+
+	// Only redraw on changes, not on every frame
+	state := &u.lyricsState
+	if state.shown && state.shownURI == uri && state.shownDone == entry.done && state.shownLine == line {
+		u.mu.Unlock()
+		return
+	}
+	newTrack := !state.shown || state.shownURI != uri
+	state.shown, state.shownURI, state.shownDone, state.shownLine = true, uri, entry.done, line
+	u.mu.Unlock()
+
+	u.app.QueueUpdateDraw(func() {
+		u.lyricsPanel.SetText(text)
+		switch {
+		case line >= 0:
+			// Keep the sung line in the middle of the panel
+			_, _, _, height := u.lyricsPanel.GetInnerRect()
+			u.lyricsPanel.ScrollTo(max(line-height/2, 0), 0)
+		case newTrack:
+			u.lyricsPanel.ScrollToBeginning()
+		}
+	})
+}
+
+// syncedLyrics formats synced lyrics with the line at index current
+// highlighted
+func (u *UI) syncedLyrics(lines []lyrics.Line, current int) string {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		text := tview.Escape(line.Text)
+		if text == "" {
+			text = "♪"
+		}
+		if i == current {
+			texts[i] = u.theme.accent + text + "[-]"
+		} else {
+			texts[i] = "[gray]" + text + "[-]"
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// fetchLyrics looks up the lyrics of a track and shows them if it is still
+// playing. Failures show as unavailable.
+func (u *UI) fetchLyrics(uri string, track lyrics.Track) {
+	found, err := u.lyrics.Lookup(track)
+	if err != nil {
+		found = nil
+	}
+
+	u.mu.Lock()
+	if _, ok := u.lyricsState.cache[uri]; ok {
+		u.lyricsState.cache[uri] = lyricsEntry{lyrics: found, done: true}
+	}
+	current := u.current
+	u.mu.Unlock()
+
+	if current != nil && current.Track.URI == uri {
+		u.updateLyrics(estimateProgress(current, time.Now()))
+	}
+}
+
+// lyricsTrack describes the playing track for a lyrics lookup
+func lyricsTrack(current *player.CurrentlyPlaying) lyrics.Track {
+	track := lyrics.Track{
+		Title:    current.Track.Name,
+		Album:    current.Track.Album.Name,
+		Duration: time.Duration(current.Track.Duration) * time.Millisecond,
+	}
+	if len(current.Track.Artists) > 0 {
+		track.Artist = current.Track.Artists[0].Name
+	}
+	return track
+}
@@ -0,0 +1,95 @@
+// ui/modal.go
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// confirmPage is the page name of the confirmation modal
+const confirmPage = "confirm"
+
+// confirmDestructive runs action after a yes/no prompt when the
+// confirm_destructive option is enabled, and directly otherwise.
+// It must run on the UI goroutine.
+func (u *UI) confirmDestructive(question string, action func()) {
+	if !u.config.ConfirmDestructive {
+		action()
+		return
+	}
+
+	u.confirm(question, action)
+}
+
+// confirm runs action if the user answers yes to question.
+// It must run on the UI goroutine.
+func (u *UI) confirm(question string, action func()) {
+	modal := tview.NewModal().
+		SetText(question).
+		AddButtons([]string{"No", "Yes"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			u.pages.RemovePage(confirmPage)
+			u.app.SetFocus(u.grid)
+			if buttonLabel == "Yes" {
+				action()
+			}
+		})
+
+	u.pages.AddPage(confirmPage, modal, false, true)
+	u.app.SetFocus(modal)
+}
+
+// promptPage is the page name of the text input prompt
+const promptPage = "prompt"
+
+// showPrompt opens a one-line input. onDone is called with the text when
+// Enter is pressed; if it returns an error the prompt stays open and shows
+// the error below the input. Esc closes the prompt.
+// It must run on the UI goroutine.
+func (u *UI) showPrompt(title, label string, onDone func(text string) error) {
+	errText := tview.NewTextView().
+		SetDynamicColors(true)
+
+	input := tview.NewInputField().
+		SetLabel(label).
+		SetFieldWidth(0)
+
+	closePrompt := func() {
+		u.pages.RemovePage(promptPage)
+		u.app.SetFocus(u.grid)
+	}
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			closePrompt()
+		case tcell.KeyEnter:
+			if err := onDone(input.GetText()); err != nil {
+				errText.SetText(fmt.Sprintf("[red]%v[white]", err))
+				return
+			}
+			closePrompt()
+		}
+	})
+
+	form := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(errText, 1, 0, false)
+	form.SetBorder(true).SetTitle(" " + title + " ")
+
+	u.pages.AddPage(promptPage, centered(form, 50, 4), true, true)
+	u.app.SetFocus(input)
+}
+
+// centered places p in the middle of the screen with the given size
+func centered(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+}
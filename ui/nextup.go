@@ -0,0 +1,54 @@
+// ui/nextup.go
+package ui
+
+import (
+	"fmt"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// nextUpRefreshTicks is how many update ticks pass between queue refreshes
+// when the playing item does not change, to pick up queue edits made elsewhere
+const nextUpRefreshTicks = 15
+
+// refreshNextUp fetches the head of the queue and shows it in the next-up line
+func (u *UI) refreshNextUp() {
+	queue, err := u.player.GetQueue()
+	if err != nil {
+		// The next-up line is informational, keep the previous value
+		return
+	}
+
+	text := "[gray]Next: nothing queued[white]"
+	if len(queue.QueueItems) > 0 {
+		text = fmt.Sprintf("[gray]Next: %s[white]", queue.QueueItems[0].DisplayName())
+	}
+
+	u.app.QueueUpdateDraw(func() {
+		u.nextText.SetText(text)
+	})
+}
+
+// nextUpOnChange refreshes the next-up line when the playing item changes
+func (u *UI) nextUpOnChange(change player.TrackChange) {
+	u.mu.Lock()
+	u.nextUpTicks = 0
+	u.mu.Unlock()
+
+	go u.refreshNextUp()
+}
+
+// tickNextUp refreshes the next-up line every nextUpRefreshTicks updates
+func (u *UI) tickNextUp() {
+	u.mu.Lock()
+	u.nextUpTicks++
+	due := u.nextUpTicks >= nextUpRefreshTicks
+	if due {
+		u.nextUpTicks = 0
+	}
+	u.mu.Unlock()
+
+	if due {
+		go u.refreshNextUp()
+	}
+}
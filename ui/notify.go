@@ -0,0 +1,51 @@
+// ui/notify.go
+package ui
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// notifyTrackChange shows a desktop notification for the new item. The
+// update loop only reports changes between two polls, so nothing fires
+// for the first poll or when only the progress moved.
+func (u *UI) notifyTrackChange(change player.TrackChange) {
+	if change.Current.Track.URI == "" {
+		return
+	}
+
+	title := change.Current.Track.Name
+	body := change.Current.Format("{artist} - {album}", player.Glyphs{})
+	body = strings.TrimSuffix(body, " -")
+
+	// A missing notifier is not worth interrupting the player for
+	go sendNotification(title, body)
+}
+
+// sendNotification shows a desktop notification with notify-send on Linux
+// and the BSDs, or osascript on macOS
+func sendNotification(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := "display notification " + appleScriptString(body) +
+			" with title " + appleScriptString(title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		return errors.New("desktop notifications are not supported on Windows")
+	default:
+		cmd = exec.Command("notify-send", "--app-name=spotify-tmux", title, body)
+	}
+	return cmd.Run()
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,205 @@
+// ui/playlists.go
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mesyrob/spotify-tmux/player"
+	"github.com/rivo/tview"
+)
+
+// playlistPage is the page name of the playlist browser
+const playlistPage = "playlists"
+
+// Size of the playlist browser, which scrolls when the list is longer
+const (
+	playlistWidth  = 60
+	playlistHeight = 20
+)
+
+// playlistTracksPage is the page name of a playlist's track list, shown on
+// top of the playlist browser
+const playlistTracksPage = "playlist-tracks"
+
+// showPlaylists lists the user's playlists. Selecting one lists its tracks.
+func (u *UI) showPlaylists() {
+	if !u.requirePremium() || !u.runGrant(&u.playlistGrant) {
+		return
+	}
+
+	go func() {
+		page, err := u.player.GetUserPlaylists(0, 0)
+		if err != nil {
+			u.showError(err)
+			return
+		}
+		u.app.QueueUpdateDraw(func() {
+			u.openPlaylists(page)
+		})
+	}()
+}
+
+// openPlaylists shows the playlist browser with the first page of
+// playlists. Further pages are fetched when "Load more" is selected, so
+// hundreds of playlists do not delay opening it.
+// It must run on the UI goroutine.
+func (u *UI) openPlaylists(first *player.PlaylistPage) {
+	list := tview.NewList().
+		ShowSecondaryText(false)
+
+	closeBrowser := func() {
+		u.pages.RemovePage(playlistPage)
+		u.app.SetFocus(u.grid)
+	}
+
+	var addPage func(page *player.PlaylistPage)
+	addPage = func(page *player.PlaylistPage) {
+		for _, playlist := range page.Items {
+			// Spotify returns null for playlists it cannot show
+			if playlist.URI == "" {
+				continue
+			}
+			playlist := playlist
+			label := fmt.Sprintf("%s · %d · %s", playlist.Name, playlist.TrackCount(), playlist.Owner.Name())
+			list.AddItem(tview.Escape(label), "", 0, func() {
+				u.showPlaylistTracks(playlist, list, closeBrowser)
+			})
+		}
+
+		if page.Next == "" || len(page.Items) == 0 {
+			return
+		}
+		offset := page.Offset + len(page.Items)
+		list.AddItem(fmt.Sprintf("Load more (%d of %d shown)", offset, page.Total), "", 0, func() {
+			list.RemoveItem(list.GetItemCount() - 1)
+			go func() {
+				next, err := u.player.GetUserPlaylists(0, offset)
+				if err != nil {
+					u.showError(err)
+					return
+				}
+				u.app.QueueUpdateDraw(func() {
+					addPage(next)
+				})
+			}()
+		})
+	}
+
+	addPage(first)
+	if list.GetItemCount() == 0 {
+		list.AddItem("No playlists found", "", 0, closeBrowser)
+	}
+
+	list.SetDoneFunc(closeBrowser)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' || event.Rune() == 'L' {
+			closeBrowser()
+			return nil
+		}
+		return event
+	})
+	list.SetBorder(true).SetTitle(" Playlists ")
+
+	u.pages.AddPage(playlistPage, centered(list, playlistWidth, playlistHeight), true, true)
+	u.app.SetFocus(list)
+}
+
+// showPlaylistTracks lists the tracks of a playlist. Selecting one plays the
+// playlist from there, so the rest of it follows. browser is the list of
+// the playlist browser below, which closeBrowser closes.
+func (u *UI) showPlaylistTracks(playlist player.Playlist, browser *tview.List, closeBrowser func()) {
+	go func() {
+		page, err := u.player.GetPlaylistTracks(playlist.ID, 0, 0)
+		if err != nil {
+			u.showError(err)
+			return
+		}
+		u.app.QueueUpdateDraw(func() {
+			u.openPlaylistTracks(playlist, page, browser, closeBrowser)
+		})
+	}()
+}
+
+// openPlaylistTracks shows the track list of a playlist, loading further
+// pages on demand like the playlist browser. Esc goes back to the browser.
+// It must run on the UI goroutine.
+func (u *UI) openPlaylistTracks(playlist player.Playlist, first *player.TrackPage, browser *tview.List, closeBrowser func()) {
+	list := tview.NewList().
+		ShowSecondaryText(false)
+
+	back := func() {
+		u.pages.RemovePage(playlistTracksPage)
+		u.app.SetFocus(browser)
+	}
+	play := func(position int) {
+		back()
+		closeBrowser()
+		u.runAction(func() error {
+			return u.player.PlayContext(playlist.URI, position)
+		})
+	}
+
+	list.AddItem("▶ Play from the start", "", 0, func() { play(0) })
+
+	var addPage func(page *player.TrackPage)
+	addPage = func(page *player.TrackPage) {
+		for i, item := range page.Items {
+			// Positions count every item, playable or not
+			position := page.Offset + i
+			label, playable := playlistItemLabel(item)
+			list.AddItem(tview.Escape(fmt.Sprintf("%3d. %s", position+1, label)), "", 0, func() {
+				if !playable {
+					u.showNotice("This item cannot be played from here")
+					return
+				}
+				play(position)
+			})
+		}
+
+		if page.Next == "" || len(page.Items) == 0 {
+			return
+		}
+		offset := page.Offset + len(page.Items)
+		list.AddItem(fmt.Sprintf("Load more (%d of %d shown)", offset, page.Total), "", 0, func() {
+			list.RemoveItem(list.GetItemCount() - 1)
+			go func() {
+				next, err := u.player.GetPlaylistTracks(playlist.ID, 0, offset)
+				if err != nil {
+					u.showError(err)
+					return
+				}
+				u.app.QueueUpdateDraw(func() {
+					addPage(next)
+				})
+			}()
+		})
+	}
+	addPage(first)
+
+	list.SetDoneFunc(back)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'q' {
+			back()
+			return nil
+		}
+		return event
+	})
+	list.SetBorder(true).SetTitle(" " + tview.Escape(playlist.Name) + " ")
+
+	u.pages.AddPage(playlistTracksPage, centered(list, playlistWidth, playlistHeight), true, true)
+	u.app.SetFocus(list)
+}
+
+// playlistItemLabel describes a playlist item and reports whether it can be
+// played. Spotify returns removed or region-locked tracks as null and local
+// files without a playable URI.
+func playlistItemLabel(item player.PlaylistItem) (string, bool) {
+	switch {
+	case item.Track == nil:
+		return "(unavailable)", false
+	case item.IsLocal:
+		return item.Track.DisplayName() + " (local file)", false
+	}
+	return item.Track.DisplayName(), true
+}
@@ -0,0 +1,45 @@
+// ui/progress.go
+package ui
+
+import (
+	"strings"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// minProgressWidth is the narrowest bar drawn; narrower panes get no bar
+const minProgressWidth = 5
+
+// renderProgressBar draws a bar width cells wide filled in proportion to
+// fraction, returning the filled and the empty part. It is blank for widths
+// below minProgressWidth.
+func renderProgressBar(fraction float64, width int, filled, empty string) (string, string) {
+	if width < minProgressWidth {
+		return "", ""
+	}
+
+	done := int(fraction * float64(width))
+	return strings.Repeat(filled, done), strings.Repeat(empty, width-done)
+}
+
+// updateProgressBar redraws the progress bar for a playback state.
+// It must run on the UI goroutine.
+func (u *UI) updateProgressBar(current *player.CurrentlyPlaying) {
+	_, _, width, _ := u.progressBar.GetInnerRect()
+	if u.config.ProgressBarWidth > 0 && u.config.ProgressBarWidth < width {
+		width = u.config.ProgressBarWidth
+	}
+
+	filled, empty := "█", "░"
+	if u.config.ASCIIButtons {
+		filled, empty = "#", "-"
+	}
+
+	// The bar is empty when nothing is playing
+	fraction := 0.0
+	if current.Track.URI != "" {
+		fraction = current.ProgressFraction()
+	}
+	done, rest := renderProgressBar(fraction, width, filled, empty)
+	u.progressBar.SetText(u.theme.filled + done + u.theme.empty + rest + "[-]")
+}
@@ -0,0 +1,66 @@
+// ui/queue.go
+package ui
+
+import "github.com/rivo/tview"
+
+// queuePanelRows is the height of the queue panel
+const queuePanelRows = 8
+
+// newQueueList creates the list showing the upcoming queue
+func newQueueList() *tview.List {
+	list := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true)
+	list.SetBorder(true).SetTitle(" Up next ")
+	return list
+}
+
+// toggleQueue shows or hides the queue panel
+func (u *UI) toggleQueue() {
+	u.mu.Lock()
+	u.showQueue = !u.showQueue
+	visible := u.showQueue
+	u.mu.Unlock()
+
+	u.layout()
+	if visible {
+		go u.refreshQueue()
+	}
+}
+
+// queueVisible reports whether the queue panel is shown
+func (u *UI) queueVisible() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.showQueue
+}
+
+// refreshQueue fetches the queue into the queue panel
+func (u *UI) refreshQueue() {
+	queue, err := u.player.GetQueue()
+	if err != nil {
+		// The panel is informational, keep the previous contents
+		return
+	}
+
+	names := make([]string, len(queue.QueueItems))
+	for i, item := range queue.QueueItems {
+		names[i] = tview.Escape(item.DisplayName())
+	}
+
+	u.app.QueueUpdateDraw(func() {
+		current := u.queueList.GetCurrentItem()
+		u.queueList.Clear()
+		if len(names) == 0 {
+			u.queueList.AddItem("[gray]The queue is empty[white]", "", 0, nil)
+			return
+		}
+		for _, name := range names {
+			u.queueList.AddItem(name, "", 0, nil)
+		}
+		// Keep the scroll position across refreshes
+		if current < len(names) {
+			u.queueList.SetCurrentItem(current)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+// ui/reauth.go
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mesyrob/spotify-tmux/auth"
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// needsReauth reports whether err means Spotify no longer accepts the login
+func needsReauth(err error) bool {
+	return errors.Is(err, player.ErrUnauthorized) || errors.Is(err, auth.ErrReauthRequired)
+}
+
+// SetReauth sets a function that runs the login flow again. It is run with
+// the UI suspended when the login key is pressed, which the error shown for
+// a rejected token asks for.
+func (u *UI) SetReauth(reauth func() error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.reauth = reauth
+}
+
+// reauthenticate logs in again and refreshes the track info.
+// It must run on the UI goroutine.
+func (u *UI) reauthenticate() {
+	u.mu.Lock()
+	reauth := u.reauth
+	u.mu.Unlock()
+	if reauth == nil {
+		u.showNotice("Logging in again is not possible here, restart spotify-tmux")
+		return
+	}
+
+	var err error
+	u.app.Suspend(func() {
+		fmt.Println("Logging in to Spotify again.")
+		err = reauth()
+	})
+	if err != nil {
+		u.showError(err)
+		return
+	}
+
+	u.player.Refresh()
+}
@@ -0,0 +1,279 @@
+// ui/search.go
+package ui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mesyrob/spotify-tmux/player"
+	"github.com/rivo/tview"
+)
+
+// searchPage is the page name of the search panel
+const searchPage = "search"
+
+// searchDebounce is how long typing must pause before a search is sent
+const searchDebounce = 400 * time.Millisecond
+
+// searchHelp is shown below the search input when there is nothing else to say
+const searchHelp = "Enter = play, Space = select track, a = queue selected, Esc = close"
+
+// searchResult is an entry of the search results list
+type searchResult struct {
+	kind     string
+	label    string
+	uri      string
+	selected bool
+}
+
+// searchPanel is the state of an open search panel
+type searchPanel struct {
+	input  *tview.InputField
+	status *tview.TextView
+	list   *tview.List
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	seq     int
+	results []searchResult
+}
+
+// showSearch opens the search panel
+func (u *UI) showSearch() {
+	s := &searchPanel{
+		input: tview.NewInputField().
+			SetLabel("Search: ").
+			SetFieldWidth(0),
+		status: tview.NewTextView().
+			SetDynamicColors(true).
+			SetText("[gray]" + searchHelp + "[white]"),
+		list: tview.NewList().
+			ShowSecondaryText(false),
+	}
+
+	closeSearch := func() {
+		s.mu.Lock()
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		s.seq++
+		s.mu.Unlock()
+
+		u.pages.RemovePage(searchPage)
+		u.app.SetFocus(u.grid)
+	}
+
+	// Search once typing pauses, or right away on Enter
+	s.input.SetChangedFunc(func(text string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		s.timer = time.AfterFunc(searchDebounce, func() {
+			u.runSearch(s, text)
+		})
+	})
+	s.input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			closeSearch()
+		case tcell.KeyEnter:
+			s.mu.Lock()
+			if s.timer != nil {
+				s.timer.Stop()
+			}
+			s.mu.Unlock()
+			go u.runSearch(s, s.input.GetText())
+			u.app.SetFocus(s.list)
+		case tcell.KeyTab, tcell.KeyDown:
+			u.app.SetFocus(s.list)
+		}
+	})
+
+	s.list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		result, ok := s.result(index)
+		if !ok {
+			return
+		}
+		closeSearch()
+		u.playSearchResult(result)
+	})
+	s.list.SetDoneFunc(closeSearch)
+	s.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyTab || event.Rune() == '/':
+			u.app.SetFocus(s.input)
+			return nil
+		case event.Rune() == ' ':
+			s.toggleSelected(s.list.GetCurrentItem())
+			return nil
+		case event.Rune() == 'a':
+			u.queueSearchResults(s)
+			return nil
+		}
+		return event
+	})
+
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(s.input, 1, 0, true).
+		AddItem(s.status, 1, 0, false).
+		AddItem(s.list, 0, 1, false)
+	panel.SetBorder(true).SetTitle(" Search ")
+
+	u.pages.AddPage(searchPage, centered(panel, 70, 20), true, true)
+	u.app.SetFocus(s.input)
+}
+
+// runSearch searches for text and shows the results, unless a newer
+// search was started in the meantime
+func (u *UI) runSearch(s *searchPanel, text string) {
+	s.mu.Lock()
+	s.seq++
+	seq := s.seq
+	s.mu.Unlock()
+
+	if text == "" {
+		return
+	}
+
+	u.app.QueueUpdateDraw(func() {
+		s.status.SetText("[yellow]Searching...[white]")
+	})
+
+	found, err := u.player.Search(text, nil, 10)
+
+	s.mu.Lock()
+	stale := seq != s.seq
+	if !stale && err == nil {
+		s.results = searchResults(found)
+	}
+	results := append([]searchResult(nil), s.results...)
+	s.mu.Unlock()
+
+	if stale {
+		return
+	}
+	if err != nil {
+		u.app.QueueUpdateDraw(func() {
+			s.status.SetText(fmt.Sprintf("[red]%s[white]", tview.Escape(err.Error())))
+		})
+		return
+	}
+
+	u.app.QueueUpdateDraw(func() {
+		s.list.Clear()
+		for _, r := range results {
+			s.list.AddItem(tview.Escape(r.label), "", 0, nil)
+		}
+		if len(results) == 0 {
+			s.status.SetText("[gray]No results[white]")
+			return
+		}
+		s.status.SetText("[gray]" + searchHelp + "[white]")
+	})
+}
+
+// searchResults flattens search results into list entries, tracks first
+func searchResults(found *player.SearchResults) []searchResult {
+	var results []searchResult
+	for _, t := range found.Tracks {
+		results = append(results, searchResult{kind: "track", label: "Track   " + t.DisplayName(), uri: t.URI})
+	}
+	for _, a := range found.Albums {
+		results = append(results, searchResult{kind: "album", label: "Album   " + a.Name, uri: a.URI})
+	}
+	for _, a := range found.Artists {
+		results = append(results, searchResult{kind: "artist", label: "Artist  " + a.Name, uri: a.URI})
+	}
+	return results
+}
+
+// result returns the entry at index of the results list
+func (s *searchPanel) result(index int) (searchResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index < 0 || index >= len(s.results) {
+		return searchResult{}, false
+	}
+	return s.results[index], true
+}
+
+// toggleSelected marks or unmarks a track for queueing.
+// It must run on the UI goroutine.
+func (s *searchPanel) toggleSelected(index int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index < 0 || index >= len(s.results) || s.results[index].kind != "track" {
+		return
+	}
+
+	r := &s.results[index]
+	r.selected = !r.selected
+	label := r.label
+	if r.selected {
+		label = "[x] " + label
+	}
+	s.list.SetItemText(index, tview.Escape(label), "")
+}
+
+// playSearchResult plays a track on its own, or an album or artist as a context
+func (u *UI) playSearchResult(result searchResult) {
+	if !u.requirePremium() {
+		return
+	}
+
+	u.runAction(func() error {
+		if result.kind == "track" {
+			return u.player.PlayURI("", result.uri)
+		}
+		return u.player.PlayURI(result.uri, "")
+	})
+}
+
+// queueSearchResults queues the selected tracks, or the highlighted one if
+// none is selected, reporting progress as it goes
+func (u *UI) queueSearchResults(s *searchPanel) {
+	if !u.requirePremium() {
+		return
+	}
+
+	s.mu.Lock()
+	var uris []string
+	for _, r := range s.results {
+		if r.selected {
+			uris = append(uris, r.uri)
+		}
+	}
+	if len(uris) == 0 {
+		if index := s.list.GetCurrentItem(); index >= 0 && index < len(s.results) && s.results[index].kind == "track" {
+			uris = append(uris, s.results[index].uri)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(uris) == 0 {
+		s.status.SetText("[gray]Select tracks with Space first[white]")
+		return
+	}
+
+	u.runAction(func() error {
+		failed := 0
+		err := u.player.QueueTracks(uris, func(done int, uri string, err error) {
+			if err != nil {
+				failed++
+			}
+			text := fmt.Sprintf("[yellow]Queued %d of %d[white]", done, len(uris))
+			if failed > 0 {
+				text += fmt.Sprintf(" [red](%d failed)[white]", failed)
+			}
+			u.app.QueueUpdateDraw(func() {
+				s.status.SetText(text)
+			})
+		})
+		go u.refreshNextUp()
+		return err
+	})
+}
@@ -0,0 +1,50 @@
+// ui/shuffle.go
+package ui
+
+import "github.com/mesyrob/spotify-tmux/player"
+
+// toggleShuffle flips shuffle for the current context
+func (u *UI) toggleShuffle() {
+	if !u.requirePremium() {
+		return
+	}
+
+	u.runAction(func() error {
+		state, err := u.player.GetPlaybackState()
+		if err != nil {
+			return err
+		}
+		if err := u.player.SetShuffle(!state.ShuffleState); err != nil {
+			return err
+		}
+
+		if state.ShuffleState {
+			u.showNotice("Shuffle off")
+		} else {
+			u.showNotice("Shuffle on")
+		}
+		return nil
+	})
+}
+
+// cycleRepeat moves to the next repeat mode: off, context, track
+func (u *UI) cycleRepeat() {
+	if !u.requirePremium() {
+		return
+	}
+
+	u.runAction(func() error {
+		state, err := u.player.GetPlaybackState()
+		if err != nil {
+			return err
+		}
+
+		mode := player.NextRepeatMode(state.RepeatState)
+		if err := u.player.SetRepeat(mode); err != nil {
+			return err
+		}
+
+		u.showNotice("Repeat: " + mode)
+		return nil
+	})
+}
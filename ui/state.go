@@ -0,0 +1,147 @@
+// ui/state.go
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mesyrob/spotify-tmux/config"
+	"github.com/mesyrob/spotify-tmux/player"
+	"github.com/rivo/tview"
+)
+
+// appState is what the player remembers between runs in state.json
+type appState struct {
+	// Resume is the item that was playing at the last exit
+	Resume *resumePoint `json:"resume,omitempty"`
+}
+
+// resumePoint is a position in a track or episode to resume from
+type resumePoint struct {
+	URI        string    `json:"uri"`
+	Name       string    `json:"name"`
+	PositionMs int       `json:"position_ms"`
+	SavedAt    time.Time `json:"saved_at"`
+}
+
+// stateFile returns the path of the persisted state
+func stateFile() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// loadState reads the persisted state. A missing file is an empty state.
+func loadState() (appState, error) {
+	var state appState
+
+	path, err := stateFile()
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	return state, json.Unmarshal(data, &state)
+}
+
+// saveState writes the persisted state
+func saveState(state appState) error {
+	path, err := stateFile()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// saveResumePoint remembers the current item and position on exit when
+// resume_on_start is enabled. Nothing is overwritten if nothing is playing.
+func (u *UI) saveResumePoint() {
+	current := u.currentState()
+	if !u.config.ResumeOnStart || current == nil || current.Track.URI == "" {
+		return
+	}
+
+	state, err := loadState()
+	if err != nil {
+		log.Printf("Failed to read state: %v", err)
+	}
+	state.Resume = &resumePoint{
+		URI:        current.Track.URI,
+		Name:       current.Track.DisplayName(),
+		PositionMs: current.Progress,
+		SavedAt:    time.Now(),
+	}
+
+	if err := saveState(state); err != nil {
+		log.Printf("Failed to save state: %v", err)
+	}
+}
+
+// offerResume asks whether to resume the item saved at the last exit.
+// It is only offered when resume_on_start is enabled and nothing is playing,
+// so it never interrupts playback started elsewhere.
+func (u *UI) offerResume() {
+	current := u.currentState()
+	if !u.config.ResumeOnStart || u.isReadOnly() || current == nil || current.Track.URI != "" {
+		return
+	}
+
+	state, err := loadState()
+	if err != nil || state.Resume == nil {
+		return
+	}
+	point := *state.Resume
+
+	// Spotify plays on the active device, which may not be this machine
+	question := fmt.Sprintf("Resume %s at %s?\n\nPlayback starts on your active Spotify device.",
+		tview.Escape(point.Name), player.FormatDuration(point.PositionMs))
+
+	u.app.QueueUpdateDraw(func() {
+		u.confirm(question, func() {
+			go u.resume(point)
+		})
+	})
+}
+
+// resume starts playing a saved resume point
+func (u *UI) resume(point resumePoint) {
+	err := u.player.PlayURIAt(point.URI, point.PositionMs)
+	if errors.Is(err, player.ErrItemUnavailable) {
+		// Do not offer it again on the next start
+		if state, loadErr := loadState(); loadErr == nil {
+			state.Resume = nil
+			saveState(state)
+		}
+		u.showError(fmt.Errorf("%s is no longer available", point.Name))
+		return
+	}
+	if err != nil {
+		u.showError(err)
+		return
+	}
+	u.player.Refresh()
+}
@@ -0,0 +1,165 @@
+// ui/stats.go
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mesyrob/spotify-tmux/config"
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// statsTotals are the counters shown in the stats panel
+type statsTotals struct {
+	Listening time.Duration `json:"listening"`
+	Tracks    int           `json:"tracks"`
+	Skips     int           `json:"skips"`
+}
+
+// pollSlack is how much longer than its delay a poll may take, for the
+// request itself, and still count as one interval of listening
+const pollSlack = 2 * time.Second
+
+// sessionStats accumulates listening statistics from the update loop
+type sessionStats struct {
+	mu      sync.Mutex
+	session statsTotals
+	// previous holds totals from earlier runs when persistence is enabled
+	previous *statsTotals
+	// lastPoll is the previous poll, nil if it failed, and lastDelay the
+	// delay scheduled after it
+	lastPoll  *player.CurrentlyPlaying
+	lastDelay time.Duration
+}
+
+// newSessionStats creates the stats for this run, loading the totals of
+// earlier runs when persist is set
+func newSessionStats(persist bool) *sessionStats {
+	s := &sessionStats{}
+	if !persist {
+		return s
+	}
+
+	s.previous = &statsTotals{}
+	if path, err := statsFile(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, s.previous)
+		}
+	}
+	return s
+}
+
+// addListening adds the time played since the previous poll. It must see
+// every poll in order, failed ones as nil, with the delay scheduled after
+// it. A gap longer than the delay scheduled after the previous poll (e.g.
+// after a suspend) only counts as that delay.
+func (s *sessionStats) addListening(cur *player.CurrentlyPlaying, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, prevDelay := s.lastPoll, s.lastDelay
+	s.lastPoll, s.lastDelay = cur, delay
+	if prev == nil || cur == nil || !prev.IsPlaying {
+		return
+	}
+
+	elapsed := cur.FetchedAt.Sub(prev.FetchedAt)
+	if elapsed <= 0 {
+		return
+	}
+	if elapsed > prevDelay+pollSlack {
+		elapsed = prevDelay
+	}
+	s.session.Listening += elapsed
+}
+
+// addChange counts a change of the playing item
+func (s *sessionStats) addChange(change player.TrackChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if change.Current.Track.URI != "" {
+		s.session.Tracks++
+	}
+	if change.Reason == player.ChangeSkip {
+		s.session.Skips++
+	}
+}
+
+// String formats the stats for the stats panel
+func (s *sessionStats) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	text := fmt.Sprintf("Session: %s listening · %d tracks · %d skips",
+		s.session.Listening.Round(time.Minute), s.session.Tracks, s.session.Skips)
+
+	if s.previous != nil {
+		total := s.total()
+		text += fmt.Sprintf("  |  All time: %s · %d tracks · %d skips",
+			total.Listening.Round(time.Minute), total.Tracks, total.Skips)
+	}
+	return text
+}
+
+// total returns the totals including earlier runs; s.mu must be held
+func (s *sessionStats) total() statsTotals {
+	return statsTotals{
+		Listening: s.previous.Listening + s.session.Listening,
+		Tracks:    s.previous.Tracks + s.session.Tracks,
+		Skips:     s.previous.Skips + s.session.Skips,
+	}
+}
+
+// save persists the totals when persistence is enabled
+func (s *sessionStats) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.previous == nil {
+		return nil
+	}
+
+	path, err := statsFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s.total(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// statsFile returns the path of the persisted stats
+func statsFile() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "stats.json"), nil
+}
+
+// countTrackChange feeds track changes into the session stats
+func (u *UI) countTrackChange(change player.TrackChange) {
+	u.stats.addChange(change)
+}
+
+// toggleStats shows or hides the stats panel
+func (u *UI) toggleStats() {
+	u.mu.Lock()
+	u.showStats = !u.showStats
+	u.mu.Unlock()
+
+	u.statsText.SetText(u.stats.String())
+	u.layout()
+}
@@ -0,0 +1,46 @@
+// ui/stats_test.go
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+func TestAddListening(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration, playing bool) *player.CurrentlyPlaying {
+		return &player.CurrentlyPlaying{IsPlaying: playing, FetchedAt: start.Add(offset)}
+	}
+
+	// poll is one observation and the delay scheduled after it
+	type poll struct {
+		current *player.CurrentlyPlaying
+		delay   time.Duration
+	}
+	tests := []struct {
+		name  string
+		polls []poll
+		want  time.Duration
+	}{
+		{"steady", []poll{{at(0, true), 5 * time.Second}, {at(5200*time.Millisecond, true), 5 * time.Second}}, 5200 * time.Millisecond},
+		{"refreshed early", []poll{{at(0, true), 30 * time.Second}, {at(2*time.Second, true), 30 * time.Second}}, 2 * time.Second},
+		{"backed off", []poll{{at(0, true), time.Minute}, {at(time.Minute, true), time.Minute}}, time.Minute},
+		{"suspended", []poll{{at(0, true), time.Second}, {at(time.Hour, true), time.Second}}, time.Second},
+		{"paused", []poll{{at(0, false), time.Second}, {at(time.Second, true), time.Second}}, 0},
+		{"failed in between", []poll{{at(0, true), time.Second}, {nil, time.Second}, {at(2*time.Second, true), time.Second}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSessionStats(false)
+			for _, p := range tt.polls {
+				s.addListening(p.current, p.delay)
+			}
+			if s.session.Listening != tt.want {
+				t.Errorf("listening = %v, want %v", s.session.Listening, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// ui/theme.go
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mesyrob/spotify-tmux/config"
+)
+
+// theme holds the configured colors as tview color tags, e.g. "[green]"
+type theme struct {
+	accent string
+	err    string
+	filled string
+	empty  string
+}
+
+// newTheme checks the configured colors, replacing unknown ones with the
+// defaults, and returns a warning for each replaced color
+func newTheme(cfg config.Theme) (theme, []string) {
+	defaults := config.DefaultConfig().Theme
+	var warnings []string
+
+	tag := func(setting, color, fallback string) string {
+		if color == "" {
+			color = fallback
+		}
+		if color != fallback && tcell.GetColor(color) == tcell.ColorDefault {
+			warnings = append(warnings, fmt.Sprintf("theme: unknown %s %q, using %q", setting, color, fallback))
+			color = fallback
+		}
+		return "[" + color + "]"
+	}
+
+	return theme{
+		accent: tag("accent_color", cfg.AccentColor, defaults.AccentColor),
+		err:    tag("error_color", cfg.ErrorColor, defaults.ErrorColor),
+		filled: tag("progress_filled", cfg.ProgressFilled, defaults.ProgressFilled),
+		empty:  tag("progress_empty", cfg.ProgressEmpty, defaults.ProgressEmpty),
+	}, warnings
+}
@@ -0,0 +1,49 @@
+// ui/trackend.go
+package ui
+
+import (
+	"time"
+
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// trackEndRetry is how soon after the expected end the next poll happens
+// when a poll just before the end still found the old item
+const trackEndRetry = 250 * time.Millisecond
+
+// schedulePoll is the shared poller's Poller. Besides returning the delay
+// before the next poll, it counts the listening time, as it sees every poll
+// in order and knows how long the poller waited.
+func (u *UI) schedulePoll(current *player.CurrentlyPlaying) time.Duration {
+	delay := u.nextPoll(current)
+	u.stats.addListening(current, delay)
+	return delay
+}
+
+// nextPoll returns the delay before the next poll. It is the poller's
+// delay, shortened so a poll happens track_end_refresh before the playing
+// item should end and the next one shows up without waiting a full interval.
+func (u *UI) nextPoll(current *player.CurrentlyPlaying) time.Duration {
+	delay := u.poller.Next(current)
+
+	lead := time.Duration(u.config.TrackEndRefresh)
+	if lead <= 0 || current == nil || !current.IsPlaying || current.Track.Duration <= 0 {
+		return delay
+	}
+
+	remaining := current.Remaining()
+	if remaining <= 0 {
+		// Already at the end, leave it to the poller
+		return delay
+	}
+
+	untilEnd := remaining - lead
+	if untilEnd <= 0 {
+		// Polled within the lead time but the item has not ended yet
+		untilEnd = remaining + trackEndRetry
+	}
+	if untilEnd < delay {
+		return untilEnd
+	}
+	return delay
+}
@@ -2,13 +2,20 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
-	"github.com/rivo/tview"
+	"github.com/mesyrob/spotify-tmux/auth"
+	"github.com/mesyrob/spotify-tmux/config"
+	"github.com/mesyrob/spotify-tmux/lyrics"
 	"github.com/mesyrob/spotify-tmux/player"
+	"github.com/mesyrob/spotify-tmux/scrobble"
+	"github.com/rivo/tview"
 )
 
 // PlayerController defines the interface for player control
@@ -18,151 +25,1026 @@ type PlayerController interface {
 	Next() error
 	Previous() error
 	PlayPause() error
+	Seek(positionMs int) error
+	SeekRelative(deltaMs int) error
+	PlayURI(contextURI, trackURI string) error
+	PlayURIAt(uri string, positionMs int) error
+	ClearQueue() error
+	Search(query string, types []string, limit int) (*player.SearchResults, error)
+	QueueTracks(uris []string, progress func(done int, uri string, err error)) error
+	ListDevices() ([]player.Device, error)
+	SetVolume(percent int) error
+	TransferPlayback(deviceID string, play bool) error
+	GetPlaybackState() (*player.PlaybackState, error)
+	SetShuffle(state bool) error
+	SetRepeat(mode string) error
+	GetUserProfile() (*player.UserProfile, error)
+	GetQueue() (*player.Queue, error)
+	SetPlaybackSpeed(speed float64) error
 	GetCurrentlyPlaying() (*player.CurrentlyPlaying, error)
+	GetRecentlyPlayed(limit int, after int64) (*player.RecentlyPlayed, error)
+	GetRecentlyPlayedBefore(limit int, before int64) (*player.RecentlyPlayed, error)
+	PlayTracks(uris []string) error
+	GetUserPlaylists(limit, offset int) (*player.PlaylistPage, error)
+	GetAudioFeatures(trackID string) (*player.AudioFeatures, error)
+	GetPlaylistTracks(playlistID string, limit, offset int) (*player.TrackPage, error)
+	PlayContext(contextURI string, offset int) error
+	IsTrackSaved(uri string) (bool, error)
+	ToggleSaveCurrentTrack() (bool, error)
 	FormatTrackInfo() (string, error)
+	Format(current *player.CurrentlyPlaying) string
+	Subscribe() (<-chan player.CurrentlyPlaying, func())
+	Refresh()
+	SetPoller(poller player.Poller)
+	SetPollErrorHandler(handler func(error))
 }
 
 // UI handles the terminal user interface
 type UI struct {
-	app       *tview.Application
-	player    PlayerController
-	infoText  *tview.TextView
-	stopChan  chan struct{}
+	app         *tview.Application
+	player      PlayerController
+	config      config.Config
+	pages       *tview.Pages
+	grid        *tview.Grid
+	infoText    *tview.TextView
+	progressBar *tview.TextView
+	queueList   *tview.List
+	lyricsPanel *tview.TextView
+	buttonBar   *tview.Flex
+	prevButton  *tview.Button
+	playButton  *tview.Button
+	nextButton  *tview.Button
+	shortcuts   *tview.TextView
+	statsText   *tview.TextView
+	banner      *tview.TextView
+	nextText    *tview.TextView
+	busyText    *tview.TextView
+	bookmarks   *tview.TextView
+	warning     *tview.TextView
+	deviceText  *tview.TextView
+	albumArt    *albumArt
+	labels      config.ButtonLabels
+	theme       theme
+	// themeWarnings name the configured colors that were not recognised
+	themeWarnings []string
+	keymap        keymap
+	stopChan      chan struct{}
+	// stopOnce makes Stop safe to call more than once
+	stopOnce sync.Once
+	// runMu guards running, which is set once Run handles updates. Stop
+	// only stops the application when it is running; until then Start
+	// does it, so a Stop while starting is not lost.
+	runMu     sync.Mutex
+	running   bool
 	updateInt time.Duration
+	poller    player.Poller
+
+	mu        sync.Mutex
+	current   *player.CurrentlyPlaying
+	pinned    string
+	stopAtEnd bool
+	stats     *sessionStats
+	showStats bool
+	showHelp  bool
+	showQueue bool
+	// showLyrics is set while the lyrics panel is shown, which gets its
+	// lyrics from the lyrics provider
+	showLyrics  bool
+	lyrics      lyrics.Provider
+	lyricsState lyricsState
+	readOnly    bool
+	loop        *abLoop
+
+	nextUpTicks int
+	speedIndex  int
+	
+	// warningShown is set while a warning is displayed, warningTimer hides it
+	warningShown bool
+	warningTimer *time.Timer
+	
+	// busy counts player actions in flight, spinning is set while the
+	// busy indicator is animated
+	busy     int
+	spinning bool
+
+	// lastActivity is the last input in the UI or playback start observed
+	// in the update loop; idlePaused is set once the idle pause fired
+	lastActivity time.Time
+	idlePaused   bool
+
+	trackChangeHandlers []func(player.TrackChange)
+	
+	// scrobbles, when set, submits listens to Last.fm
+	scrobbles *scrobble.Tracker
+	
+	// failingSince is when polling started failing, zero while it works
+	failingSince time.Time
+	// errorShown is set while an error replaces the track info, so the
+	// progress is not redrawn over it until the next poll
+	errorShown bool
+	
+	liked likedState
+	
+	// features caches audio features by track ID, nil while a lookup is
+	// running or after it failed
+	features map[string]*player.AudioFeatures
+	
+	// controlGrant, libraryGrant, historyGrant and playlistGrant, when set,
+	// obtain the permissions for playback control, Liked Songs, the
+	// listening history and the user's playlists before they are first used
+	controlGrant  func() error
+	libraryGrant  func() error
+	historyGrant  func() error
+	playlistGrant func() error
+	
+	// reauth, when set, runs the login flow again after Spotify rejected
+	// the token
+	reauth func() error
 }
 
 // NewUI creates a new terminal UI
-func NewUI(player PlayerController) *UI {
+func NewUI(player PlayerController, cfg config.Config) *UI {
 	app := tview.NewApplication()
 	infoText := tview.NewTextView().
 		SetTextAlign(tview.AlignCenter).
 		SetDynamicColors(true)
 	
-	return &UI{
+	u := &UI{
 		app:       app,
 		player:    player,
+		config:    cfg,
 		infoText:  infoText,
+		labels:    buttonLabels(cfg),
+		keymap:    newKeymap(cfg),
 		stopChan:  make(chan struct{}),
-		updateInt: 1 * time.Second,
+		updateInt: time.Duration(cfg.PollInterval),
+		stopAtEnd: cfg.StopAtContextEnd,
+		stats:     newSessionStats(cfg.PersistStats),
+		showHelp:  !cfg.HideShortcuts,
+
+		// Index of 1x in podcastSpeeds
+		speedIndex: 2,
+
+		lastActivity: time.Now(),
+	}
+	u.poller = newPoller(cfg, u.updateInt)
+	u.lyrics = lyrics.NewLRCLib(cfg.LyricsURL)
+	u.theme, u.themeWarnings = newTheme(cfg.Theme)
+	
+	u.OnTrackChange(u.stopAtContextEnd)
+	u.OnTrackChange(u.countTrackChange)
+	u.OnTrackChange(u.clearLoop)
+	u.OnTrackChange(u.nextUpOnChange)
+	if cfg.Notifications {
+		u.OnTrackChange(u.notifyTrackChange)
+	}
+	if cfg.LastFM.Enabled() {
+		u.scrobbles = scrobble.NewTracker(scrobble.NewLastFM(cfg.LastFM.APIKey, cfg.LastFM.APISecret, cfg.LastFM.SessionKey))
+		u.OnTrackChange(u.scrobbles.TrackChanged)
 	}
+	
+	return u
+}
+
+// newPoller returns the poller for the configured strategy, falling back to
+// fixed polling; the strategy was already validated when loading the config
+func newPoller(cfg config.Config, interval time.Duration) player.Poller {
+	poller, err := player.NewPoller(cfg.PollStrategy, interval, cfg.PollBackoffAfter, time.Duration(cfg.PollBackoffMax))
+	if err != nil {
+		return player.FixedPoller{Interval: interval}
+	}
+	return poller
 }
 
 // Start starts the UI
 func (u *UI) Start() {
+	if u.stopped() {
+		return
+	}
+	
 	// Create main layout
-	grid := tview.NewGrid().
-		SetRows(1, 1, 1).
+	u.grid = tview.NewGrid().
 		SetColumns(0)
 	
 	// Create buttons
-	prevButton := tview.NewButton("◀ Previous").
-		SetSelectedFunc(func() {
-			if err := u.player.Previous(); err != nil {
-				u.showError(err)
-			}
-		})
+	u.prevButton = tview.NewButton(u.labels.Previous).
+		SetSelectedFunc(u.previous)
 	
-	playButton := tview.NewButton("▶ Play/Pause").
-		SetSelectedFunc(func() {
-			if err := u.player.PlayPause(); err != nil {
-				u.showError(err)
-			}
-		})
+	u.playButton = tview.NewButton(u.labels.Play).
+		SetSelectedFunc(u.playPause)
 	
-	nextButton := tview.NewButton("Next ▶").
-		SetSelectedFunc(func() {
-			if err := u.player.Next(); err != nil {
-				u.showError(err)
-			}
-		})
+	u.nextButton = tview.NewButton(u.labels.Next).
+		SetSelectedFunc(u.next)
+	
+	u.progressBar = tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true)
+	
+	u.queueList = newQueueList()
+	u.lyricsPanel = newLyricsPanel()
+	
+	u.busyText = tview.NewTextView().
+		SetTextAlign(tview.AlignCenter)
 	
 	// Create button bar
-	buttonBar := tview.NewFlex().
-		AddItem(prevButton, 0, 1, false).
-		AddItem(playButton, 0, 1, false).
-		AddItem(nextButton, 0, 1, false)
+	u.buttonBar = tview.NewFlex().
+		AddItem(u.prevButton, 0, 1, false).
+		AddItem(u.playButton, 0, 1, false).
+		AddItem(u.nextButton, 0, 1, false).
+		AddItem(u.busyText, 2, 0, false)
+	
+	u.shortcuts = tview.NewTextView().
+		SetText(u.keymap.shortcutsText()).
+		SetTextAlign(tview.AlignCenter)
+	
+	u.banner = tview.NewTextView().
+		SetText("Read-only (Spotify Free)").
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(tcell.ColorYellow)
+	
+	u.nextText = tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true)
+	
+	u.deviceText = tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true)
+	
+	u.warning = tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(tcell.ColorYellow)
+	
+	u.bookmarks = tview.NewTextView().
+		SetText(bookmarkHelp(u.config.Bookmarks)).
+		SetTextAlign(tview.AlignCenter)
+	
+	if u.config.ShowAlbumArt {
+		u.albumArt = newAlbumArt()
+	}
+	
+	u.statsText = tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true)
 	
 	// Add elements to grid
-	grid.AddItem(u.infoText, 0, 0, 1, 1, 0, 0, false)
-	grid.AddItem(buttonBar, 1, 0, 1, 1, 0, 0, true)
-	grid.AddItem(tview.NewTextView().
-		SetText("Shortcuts: p = play/pause, n = next, b = previous, q = quit").
-		SetTextAlign(tview.AlignCenter), 2, 0, 1, 1, 0, 0, false)
+	u.layout()
 	
 	// Set up keyboard shortcuts
-	grid.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Rune() {
-		case 'q':
-			u.app.Stop()
-			return nil
-		case 'p':
-			if err := u.player.PlayPause(); err != nil {
-				u.showError(err)
-			}
-			return nil
-		case 'n':
-			if err := u.player.Next(); err != nil {
-				u.showError(err)
-			}
-			return nil
-		case 'b':
-			if err := u.player.Previous(); err != nil {
-				u.showError(err)
-			}
-			return nil
-		}
+	u.grid.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return u.keymap.handle(u, event)
+	})
+	
+	// Any key or mouse input counts as activity for the idle pause
+	u.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		u.markActivity()
 		return event
 	})
+	u.app.SetMouseCapture(func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+		if action != tview.MouseMove {
+			u.markActivity()
+		}
+		return event, action
+	})
 	
 	// Start auto-update
 	go u.updateLoop()
 	
+	// The application can only be stopped once Run set up the screen, so
+	// apply a Stop that came in while starting from the first update.
+	// QueueUpdate waits for Run, hence the goroutine.
+	go u.app.QueueUpdate(func() {
+		u.runMu.Lock()
+		u.running = true
+		stop := u.stopped()
+		u.runMu.Unlock()
+		
+		if stop {
+			u.app.Stop()
+		}
+	})
+	
 	// Set root and start
-	if err := u.app.SetRoot(grid, true).EnableMouse(true).Run(); err != nil {
+	u.pages = tview.NewPages().
+		AddPage("main", u.grid, true, true)
+	if err := u.app.SetRoot(u.pages, true).EnableMouse(true).Run(); err != nil {
 		log.Fatalf("Error running application: %v", err)
 	}
+	
+	if err := u.stats.save(); err != nil {
+		log.Printf("Failed to save listening stats: %v", err)
+	}
+	u.saveResumePoint()
+	u.saveVolume()
 }
 
-// Stop stops the UI
+// layout arranges the visible rows in the main grid
+func (u *UI) layout() {
+	var rows []tview.Primitive
+	
+	rows = append(rows, u.deviceText)
+	
+	u.mu.Lock()
+	if u.readOnly {
+		rows = append(rows, u.banner)
+	}
+	if u.warningShown {
+		rows = append(rows, u.warning)
+	}
+	if u.albumArt != nil {
+		rows = append(rows, u.albumArt)
+	}
+	rows = append(rows, u.infoText, u.progressBar)
+	if u.showQueue {
+		rows = append(rows, u.queueList)
+	}
+	if u.showLyrics {
+		rows = append(rows, u.lyricsPanel)
+	}
+	rows = append(rows, u.nextText, u.buttonBar)
+	if u.showStats {
+		rows = append(rows, u.statsText)
+	}
+	if u.showHelp {
+		rows = append(rows, u.shortcuts)
+		if len(u.config.Bookmarks) > 0 {
+			rows = append(rows, u.bookmarks)
+		}
+	}
+	u.mu.Unlock()
+	
+	heights := make([]int, len(rows))
+	for i, row := range rows {
+		heights[i] = 1
+		switch {
+		case row == u.queueList:
+			heights[i] = queuePanelRows
+		case row == u.lyricsPanel:
+			heights[i] = lyricsPanelRows
+		case u.albumArt != nil && row == u.albumArt:
+			heights[i] = albumArtRows
+		}
+	}
+	
+	u.grid.Clear().SetRows(heights...)
+	for i, row := range rows {
+		u.grid.AddItem(row, i, 0, 1, 1, 0, 0, row == u.buttonBar)
+	}
+}
+
+// toggleShortcuts shows or hides the shortcut hint row
+func (u *UI) toggleShortcuts() {
+	u.mu.Lock()
+	u.showHelp = !u.showHelp
+	u.mu.Unlock()
+	
+	u.layout()
+}
+
+// Stop stops the UI. It may be called more than once, and before Start,
+// in which case Start returns at once.
 func (u *UI) Stop() {
-	close(u.stopChan)
-	u.app.Stop()
+	u.stopOnce.Do(func() {
+		u.runMu.Lock()
+		close(u.stopChan)
+		running := u.running
+		u.runMu.Unlock()
+		
+		if running {
+			u.app.Stop()
+		}
+	})
+}
+
+// stopped reports whether Stop was called
+func (u *UI) stopped() bool {
+	select {
+	case <-u.stopChan:
+		return true
+	default:
+		return false
+	}
 }
 
-// updateLoop periodically updates the track info
+// updateLoop shows each playback state the player's shared poller
+// publishes, and has it poll as often as the poller says. Anything else that
+// changes the playback state asks the player to refresh rather than fetching
+// the state itself, so there is a single source of updates.
 func (u *UI) updateLoop() {
-	ticker := time.NewTicker(u.updateInt)
-	defer ticker.Stop()
+	if len(u.themeWarnings) > 0 {
+		u.showNotice(strings.Join(u.themeWarnings, "; "))
+	}
+	
+	u.player.SetPoller(player.PollerFunc(u.schedulePoll))
+	u.player.SetPollErrorHandler(u.pollFailed)
+	states, cancel := u.player.Subscribe()
+	defer cancel()
 	
-	// Update immediately on start
-	u.updateTrackInfo()
+	u.detectReadOnly()
+	u.refreshNextUp()
+	u.restoreVolume()
 	
+	frames := time.NewTicker(frameInterval)
+	defer frames.Stop()
+	
+	// The resume point is offered once the first state is known
+	offered := false
 	for {
 		select {
-		case <-ticker.C:
-			u.updateTrackInfo()
+		case <-frames.C:
+			u.redrawProgress()
+		case current, ok := <-states:
+			if !ok {
+				return
+			}
+			u.showState(&current)
+			if !offered {
+				offered = true
+				u.offerResume()
+			}
+			u.tickNextUp()
+			if u.queueVisible() {
+				go u.refreshQueue()
+			}
 		case <-u.stopChan:
 			return
 		}
 	}
 }
 
-// updateTrackInfo updates the track information display
-func (u *UI) updateTrackInfo() {
-	info, err := u.player.FormatTrackInfo()
+// showState updates the track information display with a polled state
+func (u *UI) showState(current *player.CurrentlyPlaying) {
+	device := deviceHeader(current.Device)
+	
+	u.mu.Lock()
+	previous := u.current
+	u.current = current
+	u.failingSince = time.Time{}
+	u.errorShown = false
+	handlers := u.trackChangeHandlers
+	u.mu.Unlock()
+	
+	// Playback started or moved on elsewhere, e.g. from a phone
+	if current.IsPlaying && (previous == nil || !previous.IsPlaying || previous.Track.URI != current.Track.URI) {
+		u.markActivity()
+	}
+	u.checkIdle(current)
+	u.checkLoop(current)
+	
+	if change, ok := player.DetectChange(previous, current); ok {
+		for _, handler := range handlers {
+			handler(change)
+		}
+	}
+	
+	// After the handlers, so the tracker already watches a new item
+	if u.scrobbles != nil {
+		u.scrobbles.Observe(current)
+	}
+	
+	if u.albumArt != nil {
+		u.updateAlbumArt(current)
+	}
+	u.updateLyrics(current)
+	
+	info := u.infoLine(current)
+	stats := u.stats.String()
+	
+	u.app.QueueUpdateDraw(func() {
+		u.deviceText.SetText(device)
+		u.statsText.SetText(stats)
+		u.updateControls(current)
+		u.updateProgressBar(current)
+		u.infoText.SetText(info)
+	})
+}
+
+// redrawInfo redraws the info line from the last polled state, for settings
+// shown in it that changed without the playback state changing
+func (u *UI) redrawInfo() {
+	u.mu.Lock()
+	current, errorShown := u.current, u.errorShown
+	u.mu.Unlock()
+	if current == nil || errorShown {
+		return
+	}
+	
+	info := u.infoLine(current)
+	u.app.QueueUpdateDraw(func() {
+		u.infoText.SetText(info)
+	})
+}
+
+// infoLine formats the track info line for a playback state
+func (u *UI) infoLine(current *player.CurrentlyPlaying) string {
+	u.mu.Lock()
+	pinned := u.pinned
+	stopAtEnd := u.stopAtEnd
+	loop := u.loop
+	u.mu.Unlock()
+	
+	// Keep showing the pinned track while still polling in the background
+	if pinned != "" {
+		return fmt.Sprintf("[yellow]PINNED[white] %s%s[-]", u.theme.accent, pinned)
+	}
+	
+	info := u.player.Format(current) + u.likeBadge(current)
+	if current.Track.URI == "" {
+		info = "Nothing playing"
+	}
+	
+	// Podcast listeners care more about what is left than what has passed
+	if current.IsEpisode() && current.Track.Duration > 0 {
+		info += fmt.Sprintf("  [yellow]-%s left[white]", player.FormatDuration(int(current.Remaining().Milliseconds())))
+	}
+	
+	if u.config.ShowDetails {
+		info += trackDetails(current)
+	}
+	if u.config.ShowAudioFeatures {
+		info += u.featuresBadge(current)
+	}
+	
+	if stopAtEnd {
+		info += "  [yellow](stop at end)[white]"
+	}
+	if loop != nil && loop.trackURI == current.Track.URI {
+		info += fmt.Sprintf("  [yellow](%s)[white]", loop)
+	}
+	
+	return fmt.Sprintf("%s%s[-]", u.theme.accent, info)
+}
+
+// pin freezes the info line on the current track until unpinned
+func (u *UI) pin() {
+	current := u.currentState()
+	if current == nil || current.Track.Name == "" {
+		u.showError(errors.New("nothing to pin"))
+		return
+	}
+	
+	u.mu.Lock()
+	u.pinned = u.player.Format(current)
+	u.mu.Unlock()
+	
+	// Redraw off the event goroutine, which QueueUpdateDraw must not block
+	go u.redrawInfo()
+}
+
+// unpin resumes live updates of the info line
+func (u *UI) unpin() {
+	u.mu.Lock()
+	u.pinned = ""
+	u.mu.Unlock()
+	go u.redrawInfo()
+}
+
+// OnTrackChange registers a handler called from the update loop whenever
+// the playing item changes. It is not called for the very first update.
+func (u *UI) OnTrackChange(handler func(player.TrackChange)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.trackChangeHandlers = append(u.trackChangeHandlers, handler)
+}
+
+// stopAtContextEnd pauses playback when a context finished and Spotify moved
+// on to something else by itself.
+//
+// The Web API can neither toggle autoplay nor tell whether it is active, and
+// the queue is never empty while autoplay is on because Spotify fills it with
+// recommendations. The reliable signal is the context changing right as the
+// previous item played to its end, which is what this handler looks for.
+func (u *UI) stopAtContextEnd(change player.TrackChange) {
+	u.mu.Lock()
+	enabled := u.stopAtEnd
+	u.mu.Unlock()
+	
+	if !enabled || u.isReadOnly() || change.Reason != player.ChangeContext || !change.PlayedToEnd {
+		return
+	}
+	
+	if err := u.player.Pause(); err != nil {
+		u.showError(err)
+	}
+}
+
+// markActivity records user activity for the idle pause
+func (u *UI) markActivity() {
+	u.mu.Lock()
+	u.lastActivity = time.Now()
+	u.idlePaused = false
+	u.mu.Unlock()
+}
+
+// checkIdle pauses playback once the idle pause threshold has passed
+func (u *UI) checkIdle(current *player.CurrentlyPlaying) {
+	threshold := time.Duration(u.config.IdlePause)
+	if threshold <= 0 || !current.IsPlaying || u.isReadOnly() {
+		return
+	}
+	
+	u.mu.Lock()
+	idle := time.Since(u.lastActivity)
+	if idle < threshold || u.idlePaused {
+		u.mu.Unlock()
+		return
+	}
+	u.idlePaused = true
+	u.mu.Unlock()
+	
+	if err := u.player.Pause(); err != nil {
+		u.showError(err)
+	}
+}
+
+// toggleStopAtEnd flips the stop-at-end-of-context mode for this session
+func (u *UI) toggleStopAtEnd() {
+	u.mu.Lock()
+	u.stopAtEnd = !u.stopAtEnd
+	u.mu.Unlock()
+	go u.redrawInfo()
+}
+
+// seekPosition parses a timestamp typed at the seek prompt into a position
+// in milliseconds within current
+func seekPosition(text string, current *player.CurrentlyPlaying) (int, error) {
+	position, err := player.ParseTimestamp(text)
+	if err != nil {
+		return 0, err
+	}
+	
+	if current == nil || current.Track.URI == "" {
+		return 0, player.ErrNothingPlaying
+	}
+	if !current.CanSeek() {
+		return 0, errDisallowed
+	}
+	if current.Track.Duration > 0 && position >= current.Track.Duration {
+		return 0, fmt.Errorf("%s is past the end (%s)", text, player.FormatDuration(current.Track.Duration))
+	}
+	return position, nil
+}
+
+// promptSeek asks for a timestamp and seeks there
+func (u *UI) promptSeek() {
+	if !u.requirePremium() {
+		return
+	}
+	
+	u.showPrompt("Seek to", "Time (m:ss or h:mm:ss): ", func(text string) error {
+		position, err := seekPosition(text, u.currentState())
+		if err != nil {
+			return err
+		}
+		
+		if err := u.player.Seek(position); err != nil {
+			return err
+		}
+		u.player.Refresh()
+		return nil
+	})
+}
+
+// podcastSpeeds are the speeds the speed keys step through
+var podcastSpeeds = []float64{0.5, 0.8, 1, 1.2, 1.5, 1.8, 2, 2.5, 3, 3.5}
+
+// changeSpeed steps the podcast playback speed up (1) or down (-1).
+// The control is only offered while an episode is playing.
+func (u *UI) changeSpeed(direction int) {
+	if !u.requirePremium() {
+		return
+	}
+	current := u.currentState()
+	if current == nil || !current.IsEpisode() {
+		u.showError(errors.New("playback speed is only available while an episode is playing"))
+		return
+	}
+	
+	u.mu.Lock()
+	index := u.speedIndex + direction
+	u.mu.Unlock()
+	if index < 0 || index >= len(podcastSpeeds) {
+		return
+	}
+	
+	if err := u.player.SetPlaybackSpeed(podcastSpeeds[index]); err != nil {
+		u.showError(err)
+		return
+	}
+	
+	u.mu.Lock()
+	u.speedIndex = index
+	u.mu.Unlock()
+}
+
+// errDisallowed is shown when Spotify currently forbids an action, e.g. skipping during an ad
+var errDisallowed = errors.New("this action is not available right now")
+
+// errPremiumRequired is shown when a Spotify Free user tries to control playback
+var errPremiumRequired = errors.New("controlling playback requires Spotify Premium")
+
+// detectReadOnly switches the UI to read-only mode for Spotify Free users.
+// If the tier cannot be determined the controls stay enabled.
+func (u *UI) detectReadOnly() {
+	profile, err := u.player.GetUserProfile()
+	if err != nil || !profile.IsFree() {
+		return
+	}
+	
+	u.mu.Lock()
+	u.readOnly = true
+	u.mu.Unlock()
+	
+	u.app.QueueUpdateDraw(u.layout)
+}
+
+// isReadOnly reports whether playback controls are unavailable
+func (u *UI) isReadOnly() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.readOnly
+}
+
+// requirePremium shows an error and returns false in read-only mode.
+// It also runs the control grant first, if one is pending.
+func (u *UI) requirePremium() bool {
+	if u.isReadOnly() {
+		u.showError(errPremiumRequired)
+		return false
+	}
+	return u.runGrant(&u.controlGrant)
+}
+
+// SetControlGrant sets a function that obtains the permissions for playback
+// control. It is run, with the UI suspended so it can use the terminal,
+// the first time a control is used.
+func (u *UI) SetControlGrant(grant func() error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.controlGrant = grant
+}
+
+// SetLibraryGrant sets a function that obtains the permissions for Liked
+// Songs, run like the control grant the first time a track is liked
+func (u *UI) SetLibraryGrant(grant func() error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.libraryGrant = grant
+}
+
+// SetHistoryGrant sets a function that obtains the permissions for the
+// listening history, run like the control grant when it is first needed
+func (u *UI) SetHistoryGrant(grant func() error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.historyGrant = grant
+}
+
+// SetPlaylistGrant sets a function that obtains the permissions for the
+// user's playlists, run like the control grant when they are first listed
+func (u *UI) SetPlaylistGrant(grant func() error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.playlistGrant = grant
+}
+
+// runGrant runs a pending grant, if any, and clears it once it succeeded.
+// It must run on the UI goroutine.
+func (u *UI) runGrant(grant *func() error) bool {
+	u.mu.Lock()
+	run := *grant
+	u.mu.Unlock()
+	if run == nil {
+		return true
+	}
+	
+	var err error
+	u.app.Suspend(func() {
+		fmt.Println("This needs additional permissions from Spotify.")
+		err = run()
+	})
 	if err != nil {
 		u.showError(err)
+		return false
+	}
+	
+	u.mu.Lock()
+	*grant = nil
+	u.mu.Unlock()
+	return true
+}
+
+// previous goes back to the previous track unless Spotify disallows it
+func (u *UI) previous() {
+	if !u.requirePremium() {
+		return
+	}
+	if current := u.currentState(); current != nil && !current.CanSkipPrevious() {
+		u.showError(errDisallowed)
+		return
+	}
+	u.runAction(u.player.Previous)
+}
+
+// next skips to the next track unless Spotify disallows it
+func (u *UI) next() {
+	if !u.requirePremium() {
+		return
+	}
+	if current := u.currentState(); current != nil && !current.CanSkipNext() {
+		u.showError(errDisallowed)
+		return
+	}
+	u.runAction(u.player.Next)
+}
+
+// playPause toggles playback unless Spotify disallows it
+func (u *UI) playPause() {
+	if !u.requirePremium() {
+		return
+	}
+	if current := u.currentState(); current != nil {
+		if (current.IsPlaying && !current.CanPause()) || (!current.IsPlaying && !current.CanResume()) {
+			u.showError(errDisallowed)
+			return
+		}
+	}
+	u.runAction(u.player.PlayPause)
+}
+
+// clearQueue clears the queue, after confirmation if configured
+func (u *UI) clearQueue() {
+	if !u.requirePremium() {
 		return
 	}
+	u.confirmDestructive("Clear the queue?", func() {
+		u.runAction(func() error {
+			defer u.refreshNextUp()
+			return u.player.ClearQueue()
+		})
+	})
+}
+
+// updateControls greys out the buttons for actions Spotify disallows and
+// labels the middle button with the action it will take.
+// It must run on the UI goroutine.
+func (u *UI) updateControls(current *player.CurrentlyPlaying) {
+	if current.IsPlaying {
+		u.playButton.SetLabel(u.labels.Pause)
+	} else {
+		u.playButton.SetLabel(u.labels.Play)
+	}
+	
+	readOnly := u.isReadOnly()
+	u.prevButton.SetDisabled(readOnly || !current.CanSkipPrevious())
+	u.nextButton.SetDisabled(readOnly || !current.CanSkipNext())
+	u.playButton.SetDisabled(readOnly || (current.IsPlaying && !current.CanPause()) || (!current.IsPlaying && !current.CanResume()))
+}
+
+// trackDetails formats the release date and popularity of a track
+func trackDetails(current *player.CurrentlyPlaying) string {
+	if current.IsEpisode() || current.Track.URI == "" {
+		return ""
+	}
+	
+	var details []string
+	if released := current.Track.Album.Released(); released != "" {
+		details = append(details, "released "+released)
+	}
+	details = append(details, fmt.Sprintf("popularity %d", current.Track.Popularity))
+	return fmt.Sprintf("  [gray](%s)[white]", strings.Join(details, " · "))
+}
+
+// currentState returns the most recently fetched playback state
+func (u *UI) currentState() *player.CurrentlyPlaying {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.current
+}
+
+// podcastSkip jumps forwards (direction 1) or backwards (direction -1)
+// by the configured podcast skip interval. It only applies to episodes.
+func (u *UI) podcastSkip(direction int) {
+	current := u.currentState()
+	if !u.requirePremium() {
+		return
+	}
+	if current == nil || !current.IsEpisode() {
+		u.showError(errors.New("podcast skip is only available while an episode is playing"))
+		return
+	}
+	if !current.CanSeek() {
+		u.showError(errDisallowed)
+		return
+	}
+	
+	delta := direction * u.config.PodcastSkipSeconds * 1000
+	u.runAction(func() error {
+		return u.player.SeekRelative(delta)
+	})
+}
+
+// seekStep is how far the seek keys jump, in milliseconds
+const seekStep = 10 * 1000
+
+// seekBy jumps seekStep forward (direction 1) or back (-1) in the current
+// item
+func (u *UI) seekBy(direction int) {
+	if !u.requirePremium() {
+		return
+	}
+	current := u.currentState()
+	if current == nil || current.Track.URI == "" {
+		u.showError(player.ErrNothingPlaying)
+		return
+	}
+	if !current.CanSeek() {
+		u.showError(errDisallowed)
+		return
+	}
+	
+	u.runAction(func() error {
+		return u.player.SeekRelative(direction * seekStep)
+	})
+}
+
+// pollFailed shows an error from polling the playback state, unless
+// polling only started failing within the error_grace period and there is
+// track info to keep showing, so a short network blip does not flicker
+func (u *UI) pollFailed(err error) {
+	u.mu.Lock()
+	if u.failingSince.IsZero() {
+		u.failingSince = time.Now()
+	}
+	failingFor := time.Since(u.failingSince)
+	shown := u.current != nil
+	u.mu.Unlock()
+	
+	if shown && failingFor < time.Duration(u.config.ErrorGrace) {
+		return
+	}
+	u.showError(err)
+}
+
+// warningDuration is how long a warning stays visible after it last occurred
+const warningDuration = 10 * time.Second
+
+// ShowWarning displays a problem that did not stop the player from working,
+// e.g. a change in Spotify's responses. The warning row disappears once the
+// warning has not recurred for a while. It may be called from any goroutine.
+func (u *UI) ShowWarning(err error) {
+	u.showNotice(err.Error())
+}
+
+// showNotice displays a message in the warning row for a while
+func (u *UI) showNotice(message string) {
+	u.mu.Lock()
+	if u.warningTimer != nil {
+		u.warningTimer.Stop()
+	}
+	u.warningShown = true
+	u.warningTimer = time.AfterFunc(warningDuration, func() {
+		u.mu.Lock()
+		u.warningShown = false
+		u.mu.Unlock()
+		u.app.QueueUpdateDraw(u.layout)
+	})
+	u.mu.Unlock()
 	
 	u.app.QueueUpdateDraw(func() {
-		u.infoText.SetText(fmt.Sprintf("[green]%s[white]", info))
+		u.warning.SetText(message)
+		u.layout()
 	})
 }
 
 // showError displays an error message
 func (u *UI) showError(err error) {
+	message := fmt.Sprintf("Error: %v", err)
+	
+	var netErr *player.NetworkError
+	var apiErr *player.APIError
+	color := u.theme.err
+	switch {
+	case errors.Is(err, player.ErrNothingPlaying):
+		message, color = "Nothing playing", "[yellow]"
+	case errors.Is(err, player.ErrNoActiveDevice):
+		message = "No active Spotify device, press d to pick one"
+	case needsReauth(err):
+		message = "Spotify rejected the login, press A to log in again"
+	case errors.Is(err, player.ErrRateLimited):
+		message = "Spotify is limiting requests, try again in a moment"
+	case errors.Is(err, player.ErrInsufficientScope):
+		message = fmt.Sprintf("Missing a Spotify permission, run spotify-tmux -logout and log in again (%s)", auth.ScopeHelp())
+	case errors.As(err, &netErr):
+		message = fmt.Sprintf("Offline: %v", netErr.Err)
+	case errors.As(err, &apiErr):
+		message = fmt.Sprintf("Spotify says: %s", apiErr.Message)
+	}
+	
+	u.mu.Lock()
+	u.errorShown = true
+	u.mu.Unlock()
+	
 	u.app.QueueUpdateDraw(func() {
-		u.infoText.SetText(fmt.Sprintf("[red]Error: %v[white]", err))
+		u.infoText.SetText(fmt.Sprintf("%s%s[-]", color, tview.Escape(message)))
 	})
 }
\ No newline at end of file
@@ -0,0 +1,154 @@
+// ui/ui_test.go
+package ui
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mesyrob/spotify-tmux/config"
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// offlinePlayer fails every request the update loop makes, and its
+// subscriptions never receive a state. Other methods are left to the nil
+// PlayerController and must not be called.
+type offlinePlayer struct {
+	PlayerController
+}
+
+var errOffline = errors.New("offline")
+
+func (offlinePlayer) GetPlaybackState() (*player.PlaybackState, error) { return nil, errOffline }
+func (offlinePlayer) GetCurrentlyPlaying() (*player.CurrentlyPlaying, error) {
+	return nil, errOffline
+}
+func (offlinePlayer) GetUserProfile() (*player.UserProfile, error) { return nil, errOffline }
+func (offlinePlayer) GetQueue() (*player.Queue, error)             { return nil, errOffline }
+func (offlinePlayer) SetVolume(percent int) error                  { return errOffline }
+func (offlinePlayer) Refresh()                                     {}
+func (offlinePlayer) SetPoller(poller player.Poller)               {}
+func (offlinePlayer) SetPollErrorHandler(handler func(error))      {}
+
+func (offlinePlayer) Subscribe() (<-chan player.CurrentlyPlaying, func()) {
+	ch := make(chan player.CurrentlyPlaying)
+	var once sync.Once
+	return ch, func() { once.Do(func() { close(ch) }) }
+}
+
+// newTestUI returns a UI drawing to a simulated screen, with its state
+// files in a temporary directory
+func newTestUI(t *testing.T) *UI {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("XDG_STATE_HOME", home)
+
+	u := NewUI(offlinePlayer{}, config.DefaultConfig())
+	screen := tcell.NewSimulationScreen("")
+	screen.SetSize(80, 24)
+	u.app.SetScreen(screen)
+	return u
+}
+
+// startReturns runs Start and fails the test if it does not return soon
+func startReturns(t *testing.T, u *UI) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		u.Start()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return after Stop")
+	}
+}
+
+func TestStopTwice(t *testing.T) {
+	u := newTestUI(t)
+	u.Stop()
+	u.Stop()
+
+	if !u.stopped() {
+		t.Error("stopped() = false after Stop")
+	}
+}
+
+func TestStopBeforeStart(t *testing.T) {
+	u := newTestUI(t)
+	u.Stop()
+	startReturns(t, u)
+}
+
+func TestStopWhileStarting(t *testing.T) {
+	// Stop lands at varying points of Start, including before Run is up
+	for i := 0; i < 20; i++ {
+		u := newTestUI(t)
+		go u.Stop()
+		startReturns(t, u)
+	}
+}
+
+func TestStopWhileRunning(t *testing.T) {
+	u := newTestUI(t)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		u.Stop()
+	}()
+	startReturns(t, u)
+}
+
+func TestSeekPosition(t *testing.T) {
+	song := &player.CurrentlyPlaying{Track: player.Track{URI: "spotify:track:abc", Duration: 3 * 60 * 1000}}
+	long := &player.CurrentlyPlaying{Track: player.Track{URI: "spotify:episode:abc", Duration: 2 * 60 * 60 * 1000}}
+	unknown := &player.CurrentlyPlaying{Track: player.Track{URI: "spotify:local:abc"}}
+	locked := &player.CurrentlyPlaying{
+		Track:   player.Track{URI: "spotify:track:ad", Duration: 30000},
+		Actions: player.Actions{Disallows: map[string]bool{"seeking": true}},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		current *player.CurrentlyPlaying
+		want    int
+		// fails is set for errors without a sentinel, wantErr otherwise
+		fails   bool
+		wantErr error
+	}{
+		{name: "m:ss", text: "1:30", current: song, want: 90000},
+		{name: "padded", text: " 0:05 ", current: song, want: 5000},
+		{name: "h:mm:ss", text: "1:02:03", current: long, want: 3723000},
+		{name: "unknown length", text: "9:59", current: unknown, want: 599000},
+		{name: "invalid", text: "abc", current: song, fails: true},
+		{name: "seconds only", text: "90", current: song, fails: true},
+		{name: "seconds out of range", text: "1:75", current: song, fails: true},
+		{name: "beyond the end", text: "3:00", current: song, fails: true},
+		{name: "nothing playing", text: "0:10", current: &player.CurrentlyPlaying{}, wantErr: player.ErrNothingPlaying},
+		{name: "no state", text: "0:10", wantErr: player.ErrNothingPlaying},
+		{name: "seeking disallowed", text: "0:10", current: locked, wantErr: errDisallowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := seekPosition(tt.text, tt.current)
+			switch {
+			case tt.fails:
+				if err == nil {
+					t.Fatalf("seekPosition(%q) = %d, want an error", tt.text, got)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("seekPosition(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("seekPosition(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
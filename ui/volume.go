@@ -0,0 +1,91 @@
+// ui/volume.go
+package ui
+
+import (
+	"errors"
+	"log"
+
+	"github.com/mesyrob/spotify-tmux/config"
+	"github.com/mesyrob/spotify-tmux/player"
+)
+
+// activeVolume returns the volume of the active device, or false when there
+// is no active device or it has no volume control
+func (u *UI) activeVolume() (int, bool) {
+	devices, err := u.player.ListDevices()
+	if err != nil {
+		return 0, false
+	}
+	device := player.ActiveDevice(devices)
+	if device == nil || device.VolumePercent == nil {
+		return 0, false
+	}
+	return *device.VolumePercent, true
+}
+
+// restoreVolume sets the active device to the volume saved at the last
+// exit. It is skipped when no device is active.
+func (u *UI) restoreVolume() {
+	if !u.config.RestoreVolume || u.config.LastVolume <= 0 || u.isReadOnly() {
+		return
+	}
+	if _, ok := u.activeVolume(); !ok {
+		return
+	}
+
+	if err := u.player.SetVolume(u.config.LastVolume); err != nil {
+		u.showError(err)
+	}
+}
+
+// saveVolume writes the active device's volume to the config file on exit.
+// Only last_volume is changed, the rest of the file stays as it is.
+func (u *UI) saveVolume() {
+	if !u.config.RestoreVolume {
+		return
+	}
+	volume, ok := u.activeVolume()
+	if !ok {
+		return
+	}
+
+	cfg, err := config.LoadFile()
+	if err != nil {
+		log.Printf("Failed to read config to save the volume: %v", err)
+		return
+	}
+	if cfg.LastVolume == volume {
+		return
+	}
+
+	if err := config.UpdateFile("last_volume", volume); err != nil {
+		log.Printf("Failed to save the volume: %v", err)
+	}
+}
+
+// volumeStep is how much the volume keys change the volume, in percent
+const volumeStep = 5
+
+// changeVolume raises (direction 1) or lowers (-1) the active device's
+// volume by volumeStep
+func (u *UI) changeVolume(direction int) {
+	if !u.requirePremium() {
+		return
+	}
+
+	u.runAction(func() error {
+		volume, ok := u.activeVolume()
+		if !ok {
+			return errors.New("the active device has no volume control")
+		}
+
+		volume += direction * volumeStep
+		if volume < 0 {
+			volume = 0
+		}
+		if volume > 100 {
+			volume = 100
+		}
+		return u.player.SetVolume(volume)
+	})
+}